@@ -8,6 +8,7 @@ import (
 	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 	"gopkg.in/urfave/cli.v1"
 	"os"
+	"text/tabwriter"
 )
 
 var debug bool
@@ -130,6 +131,153 @@ func main() {
 			},
 			Action: addHostInCluster,
 		},
+		{
+			Name:  "remove-host",
+			Usage: "Decommission the slave components, stop and delete all components and then remove host from cluster",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to remove host",
+				},
+				cli.StringFlag{
+					Name:  "hostname",
+					Usage: "The hostname to remove from cluster",
+				},
+				cli.Int64Flag{
+					Name:  "decommission-timeout",
+					Usage: "The maximum time in seconds to wait the decommission of each component. Set 0 to wait without limit",
+					Value: 3600,
+				},
+			},
+			Action: removeHostInCluster,
+		},
+		{
+			Name:  "move-master",
+			Usage: "Move master component from one host to another. If the move is interrupted, run it again to resume it",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name",
+				},
+				cli.StringFlag{
+					Name:  "component-name",
+					Usage: "The master component to move",
+				},
+				cli.StringFlag{
+					Name:  "from-host",
+					Usage: "The host where the component is",
+				},
+				cli.StringFlag{
+					Name:  "to-host",
+					Usage: "The host where to move the component",
+				},
+			},
+			Action: moveMasterComponent,
+		},
+		{
+			Name:  "enable-namenode-ha",
+			Usage: "Enable NameNode HA. When manual action is needed, it exit with code 2, run it again after the action to resume it",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name",
+				},
+				cli.StringFlag{
+					Name:  "nameservice",
+					Usage: "The nameservice ID of HDFS",
+				},
+				cli.StringFlag{
+					Name:  "additional-namenode-host",
+					Usage: "The host where to install the additional NameNode",
+				},
+				cli.StringFlag{
+					Name:  "journalnode-hosts",
+					Usage: "The hosts where to install the JournalNodes, separated by comma",
+				},
+			},
+			Action: enableNameNodeHA,
+		},
+		{
+			Name:  "enable-resourcemanager-ha",
+			Usage: "Enable ResourceManager HA. If it is interrupted, run it again to resume it",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name",
+				},
+				cli.StringFlag{
+					Name:  "second-host",
+					Usage: "The host where to install the additional ResourceManager",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only display the steps and the yarn-site properties, nothing is changed",
+				},
+			},
+			Action: enableResourceManagerHA,
+		},
+		{
+			Name:  "ha-state",
+			Usage: "Display the active / standby layout of NameNode, ResourceManager and HBase Master",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name",
+				},
+				cli.StringFlag{
+					Name:  "component-name",
+					Usage: "Only display this component (NAMENODE, RESOURCEMANAGER or HBASE_MASTER)",
+				},
+			},
+			Action: displayHaStates,
+		},
+		{
+			Name:  "restart-active-master",
+			Usage: "Restart the active master component to let the automatic failover elect a standby one",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name",
+				},
+				cli.StringFlag{
+					Name:  "component-name",
+					Usage: "The component to restart (NAMENODE, RESOURCEMANAGER or HBASE_MASTER)",
+				},
+				cli.Int64Flag{
+					Name:  "timeout",
+					Usage: "The maximum time in seconds to wait another component is active. Set 0 to wait without limit",
+					Value: 300,
+				},
+			},
+			Action: restartActiveMaster,
+		},
+		{
+			Name:  "download-client-config",
+			Usage: "Download the client configurations of service or component",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name",
+				},
+				cli.StringFlag{
+					Name:  "service-name",
+					Usage: "The service name",
+				},
+				cli.StringFlag{
+					Name:  "component-name",
+					Usage: "The client component name. If not set, it download the configurations of all clients of service",
+				},
+				cli.StringFlag{
+					Name:  "output-file",
+					Usage: "The file where to save the tarball",
+				},
+				cli.StringFlag{
+					Name:  "extract-directory",
+					Usage: "The directory where to extract the tarball",
+				},
+			},
+			Action: downloadClientConfig,
+		},
 		{
 			Name:  "stop-service",
 			Usage: "Stop service and wait service is stopped",
@@ -206,6 +354,290 @@ func main() {
 			},
 			Action: startAllServicesInCluster,
 		},
+		{
+			Name:  "start-services",
+			Usage: "Start services one by one in the order of their dependencies",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to start the services",
+				},
+				cli.StringFlag{
+					Name:  "service-names",
+					Usage: "A comma separated list of services to start. If not set, it start all services",
+				},
+				cli.BoolFlag{
+					Name:  "disable-maintenance",
+					Usage: "Remove maintenance state in each service before start it",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only display the order to start the services",
+				},
+			},
+			Action: startServicesInOrder,
+		},
+		{
+			Name:  "stop-services",
+			Usage: "Stop services one by one in the reverse order of their dependencies",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to stop the services",
+				},
+				cli.StringFlag{
+					Name:  "service-names",
+					Usage: "A comma separated list of services to stop. If not set, it stop all services",
+				},
+				cli.BoolFlag{
+					Name:  "enable-maintenance",
+					Usage: "Put each service in maintenance state after stop it",
+				},
+				cli.BoolFlag{
+					Name:  "force",
+					Usage: "Remove maintenance state in each service before stop it",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only display the order to stop the services",
+				},
+			},
+			Action: stopServicesInOrder,
+		},
+		{
+			Name:  "list-services",
+			Usage: "List the services in cluster with their state",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to list the services",
+				},
+				cli.StringFlag{
+					Name:  "state",
+					Usage: "Display only the services with this state (STARTED, INSTALLED, INIT, UNKNOWN, etc.)",
+				},
+				cli.StringFlag{
+					Name:  "maintenance-state",
+					Usage: "Display only the services with this maintenance state (ON or OFF)",
+				},
+			},
+			Action: listServicesInCluster,
+		},
+		{
+			Name:  "restart-service",
+			Usage: "Restart all components of service (except clients) and wait the restart is finished",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to restart the service",
+				},
+				cli.StringFlag{
+					Name:  "service-name",
+					Usage: "The service name to restart",
+				},
+			},
+			Action: restartServiceInCluster,
+		},
+		{
+			Name:  "restart-component",
+			Usage: "Restart component on hosts and wait the restart is finished",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to restart the component",
+				},
+				cli.StringFlag{
+					Name:  "service-name",
+					Usage: "The service name of component",
+				},
+				cli.StringFlag{
+					Name:  "component-name",
+					Usage: "The component name to restart",
+				},
+				cli.StringFlag{
+					Name:  "hostnames",
+					Usage: "A comma separated list of hosts where to restart the component. If not set, it restart the component on all hosts",
+				},
+			},
+			Action: restartComponentInCluster,
+		},
+		{
+			Name:  "rolling-restart",
+			Usage: "Restart component on all hosts in batches and wait the end",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to restart the component",
+				},
+				cli.StringFlag{
+					Name:  "component-name",
+					Usage: "The component name to restart",
+				},
+				cli.Int64Flag{
+					Name:  "batch-size",
+					Usage: "The number of hosts restarted in same time",
+					Value: 1,
+				},
+				cli.Int64Flag{
+					Name:  "batch-interval",
+					Usage: "The pause in seconds between two batches",
+					Value: 120,
+				},
+				cli.Int64Flag{
+					Name:  "tolerated-failures",
+					Usage: "The number of failed tasks tolerated in each batch before abort the rolling restart",
+					Value: 0,
+				},
+				cli.BoolFlag{
+					Name:  "only-stale-configs",
+					Usage: "Restart only the components with stale configs",
+				},
+				cli.Int64Flag{
+					Name:  "timeout",
+					Usage: "The maximum time in seconds to wait the end of rolling restart. Set 0 to wait without limit",
+					Value: 0,
+				},
+			},
+			Action: rollingRestartComponentInCluster,
+		},
+		{
+			Name:  "delete-service",
+			Usage: "Stop and delete service. It refuse to delete service if other services depend on it",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to delete the service",
+				},
+				cli.StringFlag{
+					Name:  "service-name",
+					Usage: "The service name to delete",
+				},
+				cli.BoolFlag{
+					Name:  "force",
+					Usage: "Delete the service even if other services depend on it",
+				},
+			},
+			Action: deleteServiceInCluster,
+		},
+		{
+			Name:  "maintenance",
+			Usage: "Turn on or off the maintenance mode for service, host or component on host",
+			Subcommands: []cli.Command{
+				{
+					Name:  "on",
+					Usage: "Turn on the maintenance mode",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "cluster-name",
+							Usage: "The cluster name",
+						},
+						cli.StringFlag{
+							Name:  "service-name",
+							Usage: "The service name",
+						},
+						cli.StringFlag{
+							Name:  "hostname",
+							Usage: "The hostname",
+						},
+						cli.StringFlag{
+							Name:  "component-name",
+							Usage: "The component name on host. You need to set hostname too",
+						},
+					},
+					Action: enableMaintenance,
+				},
+				{
+					Name:  "off",
+					Usage: "Turn off the maintenance mode",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "cluster-name",
+							Usage: "The cluster name",
+						},
+						cli.StringFlag{
+							Name:  "service-name",
+							Usage: "The service name",
+						},
+						cli.StringFlag{
+							Name:  "hostname",
+							Usage: "The hostname",
+						},
+						cli.StringFlag{
+							Name:  "component-name",
+							Usage: "The component name on host. You need to set hostname too",
+						},
+					},
+					Action: disableMaintenance,
+				},
+			},
+		},
+		{
+			Name:  "service-check",
+			Usage: "Run the service check of one service or of all installed services",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to run the service checks",
+				},
+				cli.StringFlag{
+					Name:  "service-name",
+					Usage: "The service name to check. If not set, it check all installed services",
+				},
+			},
+			Action: serviceCheckInCluster,
+		},
+		{
+			Name:  "custom-command",
+			Usage: "List or run the custom commands provided by stack on service and components",
+			Subcommands: []cli.Command{
+				{
+					Name:  "list",
+					Usage: "List the custom commands available on service and its components",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "cluster-name",
+							Usage: "The cluster name",
+						},
+						cli.StringFlag{
+							Name:  "service-name",
+							Usage: "The service name",
+						},
+					},
+					Action: listCustomCommands,
+				},
+				{
+					Name:  "run",
+					Usage: "Run custom command on service or on component and wait the end",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "cluster-name",
+							Usage: "The cluster name",
+						},
+						cli.StringFlag{
+							Name:  "service-name",
+							Usage: "The service name",
+						},
+						cli.StringFlag{
+							Name:  "component-name",
+							Usage: "The component name. If not set, the command is run on service",
+						},
+						cli.StringFlag{
+							Name:  "command",
+							Usage: "The custom command to run",
+						},
+						cli.StringFlag{
+							Name:  "hostnames",
+							Usage: "A comma separated list of hosts where to run the command. If not set, it run on all hosts of component",
+						},
+						cli.StringSliceFlag{
+							Name:  "parameter",
+							Usage: "A parameter of command with format name=value. It can be set several times",
+						},
+					},
+					Action: runCustomCommand,
+				},
+			},
+		},
 		{
 			Name:  "stop-all-components-in-host",
 			Usage: "Stop all components in host and wait all components are stopped",
@@ -432,6 +864,153 @@ func main() {
 			},
 			Action: addKerberos,
 		},
+		{
+			Name:  "stack",
+			Usage: "Browse the stack metadata",
+			Subcommands: []cli.Command{
+				{
+					Name:  "describe",
+					Usage: "Describe the services, components and configurations provided by stack version",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "stack-name",
+							Usage: "The stack name (like HDP)",
+						},
+						cli.StringFlag{
+							Name:  "stack-version",
+							Usage: "The stack version (like 2.6)",
+						},
+						cli.StringFlag{
+							Name:  "cluster-name",
+							Usage: "The cluster name to use its stack instead of stack-name and stack-version",
+						},
+						cli.StringFlag{
+							Name:  "service-name",
+							Usage: "The service name to describe its components",
+						},
+						cli.BoolFlag{
+							Name:  "show-configurations",
+							Usage: "Display the configuration properties of the service",
+						},
+					},
+					Action: describeStack,
+				},
+			},
+		},
+		{
+			Name:  "export-config",
+			Usage: "Export all desired configurations of cluster in directory (one file per configuration type)",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to export the configurations",
+				},
+				cli.StringFlag{
+					Name:  "directory",
+					Usage: "The directory where to write the configuration files",
+				},
+				cli.StringFlag{
+					Name:  "format",
+					Usage: "The format of configuration files (json or yaml)",
+					Value: "json",
+				},
+				cli.BoolFlag{
+					Name:  "unmask-passwords",
+					Usage: "Export the value of password properties instead of masking them",
+				},
+			},
+			Action: exportConfiguration,
+		},
+		{
+			Name:  "apply-config",
+			Usage: "Apply the configuration files on cluster and display the properties that change",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to apply the configurations",
+				},
+				cli.StringFlag{
+					Name:  "directory",
+					Usage: "The directory where to read the configuration files",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only display the properties that change without apply them",
+				},
+				cli.BoolFlag{
+					Name:  "validate",
+					Usage: "Validate the configurations with the stack advisor before apply them",
+				},
+				cli.StringSliceFlag{
+					Name:  "overlay",
+					Usage: "The directory of overlay configuration files merged over the configuration files (can be repeated, the last one win)",
+				},
+				cli.StringFlag{
+					Name:  "vars-file",
+					Usage: "The file (json or yaml) with the variables used to render the configuration files",
+				},
+				cli.StringSliceFlag{
+					Name:  "var",
+					Usage: "The variable used to render the configuration files, with format name=value (can be repeated)",
+				},
+			},
+			Action: applyConfiguration,
+		},
+		{
+			Name:  "validate-config",
+			Usage: "Validate the configuration files with the stack advisor and display errors and warnings",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cluster-name",
+					Usage: "The cluster name where to validate the configurations",
+				},
+				cli.StringFlag{
+					Name:  "directory",
+					Usage: "The directory where to read the configuration files",
+				},
+				cli.StringSliceFlag{
+					Name:  "overlay",
+					Usage: "The directory of overlay configuration files merged over the configuration files (can be repeated, the last one win)",
+				},
+				cli.StringFlag{
+					Name:  "vars-file",
+					Usage: "The file (json or yaml) with the variables used to render the configuration files",
+				},
+				cli.StringSliceFlag{
+					Name:  "var",
+					Usage: "The variable used to render the configuration files, with format name=value (can be repeated)",
+				},
+			},
+			Action: validateConfiguration,
+		},
+		{
+			Name:  "render-config",
+			Usage: "Display the configuration files after merge the overlays and render the variables",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "directory",
+					Usage: "The directory where to read the configuration files",
+				},
+				cli.StringSliceFlag{
+					Name:  "overlay",
+					Usage: "The directory of overlay configuration files merged over the configuration files (can be repeated, the last one win)",
+				},
+				cli.StringFlag{
+					Name:  "vars-file",
+					Usage: "The file (json or yaml) with the variables used to render the configuration files",
+				},
+				cli.StringSliceFlag{
+					Name:  "var",
+					Usage: "The variable used to render the configuration files, with format name=value (can be repeated)",
+				},
+				cli.StringFlag{
+					Name:  "format",
+					Usage: "The format used to display the configurations (json or yaml)",
+					Value: "yaml",
+				},
+			},
+			Action: renderConfiguration,
+		},
 	}
 
 	app.Before = func(c *cli.Context) error {
@@ -470,3 +1049,8 @@ func manageGlobalParameters() (*client.AmbariClient, error) {
 
 	return client, nil
 }
+
+// Create writer to display result as table on standard output
+func newTableWriter() *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+}
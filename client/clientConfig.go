@@ -0,0 +1,138 @@
+// This file permit to download the client configurations of service or component, like the "Download client configs" action of Ambari UI
+// It's useful to configure edge nodes that are not managed by Ambari
+
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	CLIENT_CONFIG_FORMAT = "client_config_tar"
+)
+
+// DownloadClientConfig permit to download the client configurations as tarball
+// If componentName is empty, it download the configurations of all clients of service
+// If directory is not empty, the tarball is extracted in it
+// It return the tarball
+// It return error if the service or the component not exist or if something wrong when it call the API
+func (c *AmbariClient) DownloadClientConfig(clusterName string, serviceName string, componentName string, directory string) ([]byte, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ServiceName: ", serviceName)
+	log.Debug("ComponentName: ", componentName)
+	log.Debug("Directory: ", directory)
+
+	// Ambari provide the configurations of all clients of service on the components collection
+	path := fmt.Sprintf("/clusters/%s/services/%s/components", clusterName, serviceName)
+	if componentName != "" {
+		path = fmt.Sprintf("%s/%s", path, componentName)
+	}
+	resp, err := c.Client().R().SetQueryParam("format", CLIENT_CONFIG_FORMAT).Get(path)
+	if err != nil {
+		return nil, err
+	}
+	// The body is not logged because of it's binary
+	log.Debug("Response status to download: ", resp.Status())
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			if componentName != "" {
+				return nil, NewAmbariError(404, "Component %s not found in service %s", componentName, serviceName)
+			}
+			return nil, NewAmbariError(404, "Service %s not found", serviceName)
+		}
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	log.Debugf("Download %d bytes of client configurations", len(resp.Body()))
+
+	if directory != "" {
+		err = ExtractClientConfig(resp.Body(), directory)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body(), nil
+}
+
+// ExtractClientConfig permit to extract the client configurations tarball in directory
+// The tarball can be compressed with gzip or not. The directory is created if needed.
+// It return error if the tarball is invalid or if a file is outside the directory
+func ExtractClientConfig(data []byte, directory string) error {
+
+	if directory == "" {
+		panic("Directory can't be empty")
+	}
+	log.Debug("Directory: ", directory)
+
+	var reader io.Reader = bytes.NewReader(data)
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	err := os.MkdirAll(directory, 0755)
+	if err != nil {
+		return err
+	}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		fileName := filepath.Join(directory, header.Name)
+		if fileName != filepath.Clean(directory) && strings.HasPrefix(fileName, filepath.Clean(directory)+string(os.PathSeparator)) == false {
+			return NewAmbariError(400, "File %s is outside directory %s", header.Name, directory)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(fileName, 0755)
+			if err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			err = os.MkdirAll(filepath.Dir(fileName), 0755)
+			if err != nil {
+				return err
+			}
+			file, err := os.OpenFile(fileName, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode)&0777)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, tarReader)
+			file.Close()
+			if err != nil {
+				return err
+			}
+			log.Debugf("Extract %s", fileName)
+		default:
+			log.Debugf("Skip %s because of it's not a file or a directory", header.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+func (s *ClientTestSuite) TestDownloadClientConfig() {
+
+	directory, err := ioutil.TempDir("", "client-config")
+	assert.NoError(s.T(), err)
+	defer os.RemoveAll(directory)
+
+	// Download and extract the client configurations
+	data, err := s.client.DownloadClientConfig("test", "ZOOKEEPER", "ZOOKEEPER_CLIENT", directory)
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), data)
+	files, err := ioutil.ReadDir(directory)
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), files)
+
+	// Download the client configurations of all clients of service
+	serviceDirectory, err := ioutil.TempDir("", "client-config")
+	assert.NoError(s.T(), err)
+	defer os.RemoveAll(serviceDirectory)
+	data, err = s.client.DownloadClientConfig("test", "ZOOKEEPER", "", serviceDirectory)
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), data)
+	files, err = ioutil.ReadDir(serviceDirectory)
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), files)
+
+	// Service not exist
+	_, err = s.client.DownloadClientConfig("test", "fake", "", "")
+	assert.Error(s.T(), err)
+
+	// Component not exist
+	_, err = s.client.DownloadClientConfig("test", "ZOOKEEPER", "fake", "")
+	assert.Error(s.T(), err)
+
+	// Extract gzip tarball
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	content := []byte("export JAVA_HOME=/usr/jdk64\n")
+	tarWriter.WriteHeader(&tar.Header{Name: "conf/hadoop-env.sh", Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	tarWriter.Write(content)
+	tarWriter.Close()
+	gzipWriter.Close()
+	err = ExtractClientConfig(buffer.Bytes(), directory)
+	assert.NoError(s.T(), err)
+	b, err := ioutil.ReadFile(filepath.Join(directory, "conf", "hadoop-env.sh"))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), content, b)
+
+	// File outside directory
+	buffer = &bytes.Buffer{}
+	tarWriter = tar.NewWriter(buffer)
+	tarWriter.WriteHeader(&tar.Header{Name: "../hadoop-env.sh", Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	tarWriter.Write(content)
+	tarWriter.Close()
+	err = ExtractClientConfig(buffer.Bytes(), directory)
+	assert.Error(s.T(), err)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"strings"
 )
 
 // Cluster item
@@ -17,10 +18,11 @@ type Cluster struct {
 	SessionAttributes map[string]map[string]string `json:"session_attributes,omitempty"`
 }
 type ClusterInfo struct {
-	ClusterId    int64  `json:"cluster_id,omitempty"`
-	ClusterName  string `json:"cluster_name"`
-	Version      string `json:"version,omitempty"`
-	SecurityType string `json:"security_type,omitempty"`
+	ClusterId      int64                    `json:"cluster_id,omitempty"`
+	ClusterName    string                   `json:"cluster_name"`
+	Version        string                   `json:"version,omitempty"`
+	SecurityType   string                   `json:"security_type,omitempty"`
+	DesiredConfigs map[string]Configuration `json:"desired_configs,omitempty"`
 }
 
 // String permit to return cluster object as Json string
@@ -29,6 +31,16 @@ func (c *Cluster) String() string {
 	return string(json)
 }
 
+// StackNameAndVersion permit to get the stack name and the stack version from the cluster version (like HDP-2.6)
+func (c *Cluster) StackNameAndVersion() (string, string) {
+	index := strings.LastIndex(c.ClusterInfo.Version, "-")
+	if index < 0 {
+		return c.ClusterInfo.Version, ""
+	}
+
+	return c.ClusterInfo.Version[:index], c.ClusterInfo.Version[index+1:]
+}
+
 func (c *Cluster) CleanBeforeSave() {
 	c.Services = nil
 	c.ClusterInfo = &ClusterInfo{
@@ -276,7 +288,7 @@ func (c *AmbariClient) DeleteCluster(clusterName string) error {
 		return err
 	}
 
-	// Remove all services
+	// Remove all services, we force it because of the services are removed without order
 	for _, service := range cluster.Services {
 		err = c.DeleteService(service.ServiceInfo.ClusterName, service.ServiceInfo.ServiceName)
 		if err != nil {
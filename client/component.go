@@ -10,19 +10,29 @@ import (
 )
 
 const (
+	COMPONENT_MASTER = "MASTER"
+	COMPONENT_SLAVE  = "SLAVE"
 	COMPONENT_CLIENT = "CLIENT"
 )
 
 type Component struct {
-	ComponentInfo  *ComponentInfo  `json:"ServiceComponentInfo"`
-	HostComponents []HostComponent `json:"host_components"`
+	ComponentInfo  *ComponentInfo         `json:"ServiceComponentInfo"`
+	HostComponents []HostComponent        `json:"host_components"`
+	Metrics        map[string]interface{} `json:"metrics,omitempty"`
+}
+type Components struct {
+	Items []Component `json:"items,omitempty"`
 }
 type ComponentInfo struct {
-	ClusterName   string `json:"cluster_name,omitempty"`
-	ServiceName   string `json:"service_name,omitempty"`
-	ComponentName string `json:"component_name,omitempty"`
-	State         string `json:"state,omitempty"`
-	Category      string `json:"category,omitempty"`
+	ClusterName    string                 `json:"cluster_name,omitempty"`
+	ServiceName    string                 `json:"service_name,omitempty"`
+	ComponentName  string                 `json:"component_name,omitempty"`
+	State          string                 `json:"state,omitempty"`
+	Category       string                 `json:"category,omitempty"`
+	StartedCount   int                    `json:"started_count,omitempty"`
+	InstalledCount int                    `json:"installed_count,omitempty"`
+	TotalCount     int                    `json:"total_count,omitempty"`
+	RmMetrics      map[string]interface{} `json:"rm_metrics,omitempty"`
 }
 
 // String permit to return Component as Json string
@@ -158,3 +168,105 @@ func (c *AmbariClient) DeleteComponent(clusterName string, serviceName string, c
 	return nil
 
 }
+
+// RestartComponent permit to restart component on some hosts with RESTART command
+// If hostnames is empty, it restart the component on all hosts
+// The component on host in maintenance state is not restarted
+// It return error if the component is a client or if it's not found on one host
+// It return error with the failed host if the restart failed or if something wrong when API call
+func (c *AmbariClient) RestartComponent(clusterName string, serviceName string, componentName string, hostnames []string) error {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	if componentName == "" {
+		panic("ComponentName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ServiceName: ", serviceName)
+	log.Debug("ComponentName: ", componentName)
+	log.Debug("Hostnames: ", hostnames)
+
+	return c.restartComponents(clusterName, serviceName, componentName, hostnames, fmt.Sprintf("Restart component %s from API", componentName))
+}
+
+// Hostnames permit to get the hosts where the component is installed
+func (s *Component) Hostnames() []string {
+
+	hostnames := make([]string, 0, len(s.HostComponents))
+	for _, hostComponent := range s.HostComponents {
+		if hostComponent.HostComponentInfo != nil {
+			hostnames = append(hostnames, hostComponent.HostComponentInfo.Hostname)
+		}
+	}
+
+	return hostnames
+}
+
+// ComponentsInService permit to get all components of service with their counts and their host components
+// If category is not empty, it return only the components of this category (MASTER, SLAVE or CLIENT)
+// It return nil if service not found
+// It return error if something wrong when API call
+func (c *AmbariClient) ComponentsInService(clusterName string, serviceName string, category string) ([]Component, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ServiceName: ", serviceName)
+	log.Debug("Category: ", category)
+
+	path := fmt.Sprintf("/clusters/%s/services/%s/components", clusterName, serviceName)
+	return c.components(path, category)
+}
+
+// ComponentsInCluster permit to get all components of cluster with their counts and their host components
+// If category is not empty, it return only the components of this category (MASTER, SLAVE or CLIENT)
+// It return nil if cluster not found
+// It return error if something wrong when API call
+func (c *AmbariClient) ComponentsInCluster(clusterName string, category string) ([]Component, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Category: ", category)
+
+	path := fmt.Sprintf("/clusters/%s/components", clusterName)
+	return c.components(path, category)
+}
+
+// components permit to get the components of path with the fields needed to know their placement and states
+func (c *AmbariClient) components(path string, category string) ([]Component, error) {
+
+	request := c.Client().R().SetQueryParam("fields", "ServiceComponentInfo/*,host_components/HostRoles/host_name,host_components/HostRoles/state,host_components/HostRoles/maintenance_state")
+	if category != "" {
+		request.SetQueryParam("ServiceComponentInfo/category", category)
+	}
+	resp, err := request.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			return nil, nil
+		} else {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+	}
+	components := &Components{}
+	err = json.Unmarshal(resp.Body(), components)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return %d components", len(components.Items))
+
+	return components.Items, nil
+}
@@ -43,3 +43,37 @@ func (s *ClientTestSuite) TestComponent() {
 	}
 
 }
+
+func (s *ClientTestSuite) TestComponentsInService() {
+
+	// Get all components
+	components, err := s.client.ComponentsInService("test", "ZOOKEEPER", "")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, len(components))
+
+	// Get only masters
+	components, err = s.client.ComponentsInService("test", "ZOOKEEPER", COMPONENT_MASTER)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, len(components))
+	if len(components) == 1 {
+		assert.Equal(s.T(), "ZOOKEEPER_SERVER", components[0].ComponentInfo.ComponentName)
+		assert.Equal(s.T(), components[0].ComponentInfo.TotalCount, len(components[0].Hostnames()))
+		assert.Equal(s.T(), components[0].ComponentInfo.TotalCount, components[0].ComponentInfo.StartedCount)
+	}
+
+	// Service not exist
+	components, err = s.client.ComponentsInService("test", "fake", "")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), components)
+}
+
+func (s *ClientTestSuite) TestComponentsInCluster() {
+
+	// Get only clients
+	components, err := s.client.ComponentsInCluster("test", COMPONENT_CLIENT)
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), components)
+	for _, component := range components {
+		assert.Equal(s.T(), COMPONENT_CLIENT, component.ComponentInfo.Category)
+	}
+}
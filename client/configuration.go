@@ -7,13 +7,28 @@ import (
 	"encoding/json"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+)
+
+const (
+	PROPERTY_ADDED         = "ADDED"
+	PROPERTY_UPDATED       = "UPDATED"
+	PROPERTY_REMOVED       = "REMOVED"
+	PROPERTY_TYPE_PASSWORD = "PASSWORD"
+	MASKED_VALUE           = "********"
 )
 
 // Object item
 type Configuration struct {
-	Type       string            `json:"type,omitempty"`
-	Tag        string            `json:"tag,omitempty"`
-	Properties map[string]string `json:"properties,omitempty"`
+	Type                 string                       `json:"type,omitempty"`
+	Tag                  string                       `json:"tag,omitempty"`
+	Properties           map[string]string            `json:"properties,omitempty"`
+	PropertiesAttributes map[string]map[string]string `json:"properties_attributes,omitempty"`
+	PasswordProperties   []string                     `json:"-"`
+}
+type Configurations struct {
+	Items []Configuration `json:"items,omitempty"`
 }
 type DesiredConfig struct {
 	DesiredConfig *Configuration `json:"desired_config,omitempty"`
@@ -22,12 +37,101 @@ type RequestAddConfig struct {
 	Cluster *DesiredConfig `json:"Clusters,omitempty"`
 }
 
+// PropertyChange describe the change of one property between two configurations
+type PropertyChange struct {
+	Name       string `json:"name"`
+	Action     string `json:"action"`
+	OldValue   string `json:"old_value,omitempty"`
+	NewValue   string `json:"new_value,omitempty"`
+	IsPassword bool   `json:"is_password,omitempty"`
+}
+
 // String permit to return Configuration as Json string
+// The password properties are masked. To avoid to display secrets in logs when the configuration is not marked,
+// the properties whose name ends with password are masked too.
 func (c *Configuration) String() string {
-	json, _ := json.Marshal(c)
+	if c == nil {
+		return "null"
+	}
+	configuration := c.Masked()
+	for name := range configuration.Properties {
+		if strings.HasSuffix(strings.ToLower(name), "password") {
+			configuration.Properties[name] = MASKED_VALUE
+		}
+	}
+	json, _ := json.Marshal(configuration)
 	return string(json)
 }
 
+// MarkPasswordProperties permit to set the properties with PASSWORD type from the list of password properties indexed by configuration type
+func (c *Configuration) MarkPasswordProperties(passwordProperties map[string][]string) {
+	c.PasswordProperties = passwordProperties[c.Type]
+}
+
+// IsPasswordProperty permit to know if property is marked as PASSWORD type
+func (c *Configuration) IsPasswordProperty(name string) bool {
+	for _, passwordProperty := range c.PasswordProperties {
+		if passwordProperty == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Masked permit to get a copy of configuration where the value of password properties is replaced by MASKED_VALUE
+func (c *Configuration) Masked() *Configuration {
+	configuration := *c
+	configuration.Properties = make(map[string]string, len(c.Properties))
+	for name, value := range c.Properties {
+		if c.IsPasswordProperty(name) {
+			configuration.Properties[name] = MASKED_VALUE
+		} else {
+			configuration.Properties[name] = value
+		}
+	}
+
+	return &configuration
+}
+
+// Diff permit to compute the properties that change to go from the current configuration to the target configuration
+// The current configuration can be nil, in this case all target properties are added
+// It return the list of changes sorted by property name (the list is empty if there are no change)
+func (c *Configuration) Diff(target *Configuration) []PropertyChange {
+
+	if target == nil {
+		panic("Target can't be nil")
+	}
+
+	currentProperties := make(map[string]string)
+	if c != nil && c.Properties != nil {
+		currentProperties = c.Properties
+	}
+
+	changes := make([]PropertyChange, 0, 0)
+	for name, newValue := range target.Properties {
+		isPassword := target.IsPasswordProperty(name) || (c != nil && c.IsPasswordProperty(name))
+		oldValue, isFound := currentProperties[name]
+		if isFound == false {
+			changes = append(changes, PropertyChange{Name: name, Action: PROPERTY_ADDED, NewValue: newValue, IsPassword: isPassword})
+		} else if oldValue != newValue {
+			changes = append(changes, PropertyChange{Name: name, Action: PROPERTY_UPDATED, OldValue: oldValue, NewValue: newValue, IsPassword: isPassword})
+		}
+	}
+	for name, oldValue := range currentProperties {
+		if _, isFound := target.Properties[name]; isFound == false {
+			isPassword := target.IsPasswordProperty(name) || c.IsPasswordProperty(name)
+			changes = append(changes, PropertyChange{Name: name, Action: PROPERTY_REMOVED, OldValue: oldValue, IsPassword: isPassword})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+
+	return changes
+}
+
 // CreateConfigurationOnCluster permit to add new service confoguration on cluster
 // It return cluster object if all right fine
 // It return error if something wrong
@@ -58,7 +162,8 @@ func (c *AmbariClient) CreateConfigurationOnCluster(clusterName string, configur
 	if err != nil {
 		return nil, err
 	}
-	log.Debug("Response to create: ", resp)
+	// The response is not logged because of it can contain password properties
+	log.Debug("Response status to create: ", resp.StatusCode())
 	if resp.StatusCode() >= 300 {
 		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
 	}
@@ -75,3 +180,122 @@ func (c *AmbariClient) CreateConfigurationOnCluster(clusterName string, configur
 	return cluster, err
 
 }
+
+// Configuration permit to get one configuration version from is type and tag
+// It return the configuration if found
+// It return nil if the configuration is not found
+// It return error if something wrong when it call the API
+func (c *AmbariClient) Configuration(clusterName string, configurationType string, tag string) (*Configuration, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if configurationType == "" {
+		panic("ConfigurationType can't be empty")
+	}
+	if tag == "" {
+		panic("Tag can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ConfigurationType: ", configurationType)
+	log.Debug("Tag: ", tag)
+
+	path := fmt.Sprintf("/clusters/%s/configurations", clusterName)
+	resp, err := c.Client().R().SetQueryParam("type", configurationType).SetQueryParam("tag", tag).Get(path)
+	if err != nil {
+		return nil, err
+	}
+	// The response is not logged because of it can contain password properties
+	log.Debug("Response status to get: ", resp.StatusCode())
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			return nil, nil
+		} else {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+	}
+	configurations := &Configurations{}
+	err = json.Unmarshal(resp.Body(), configurations)
+	if err != nil {
+		return nil, err
+	}
+	if len(configurations.Items) == 0 {
+		return nil, nil
+	}
+	configuration := &configurations.Items[0]
+	log.Debugf("Return configuration: %s", configuration)
+
+	return configuration, nil
+}
+
+// DesiredConfigurations permit to get all desired configurations on cluster with their properties
+// It use the desired configs of cluster to know the current tag of each configuration type
+// It return the map of configurations indexed by type
+// It return error if cluster not found or if something wrong when it call the API
+func (c *AmbariClient) DesiredConfigurations(clusterName string) (map[string]Configuration, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+
+	cluster, err := c.Cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, NewAmbariError(404, "Cluster %s not found", clusterName)
+	}
+
+	configurations := make(map[string]Configuration)
+	for configurationType, desiredConfig := range cluster.ClusterInfo.DesiredConfigs {
+		configuration, err := c.Configuration(clusterName, configurationType, desiredConfig.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if configuration == nil {
+			return nil, NewAmbariError(404, "Configuration %s with tag %s not found in cluster %s", configurationType, desiredConfig.Tag, clusterName)
+		}
+		configurations[configurationType] = *configuration
+	}
+
+	return configurations, nil
+}
+
+// PasswordProperties permit to get the properties with PASSWORD type for all services installed on cluster
+// It use the stack metadata to know the type of each property
+// It return the list of password properties indexed by configuration type
+// It return error if cluster not found or if something wrong when it call the API
+func (c *AmbariClient) PasswordProperties(clusterName string) (map[string][]string, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+
+	cluster, err := c.Cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, NewAmbariError(404, "Cluster %s not found", clusterName)
+	}
+	stackName, stackVersion := cluster.StackNameAndVersion()
+
+	passwordProperties := make(map[string][]string)
+	for _, service := range cluster.Services {
+		stackConfigurations, err := c.StackConfigurations(stackName, stackVersion, service.ServiceInfo.ServiceName)
+		if err != nil {
+			return nil, err
+		}
+		for _, stackConfiguration := range stackConfigurations {
+			if stackConfiguration.IsPassword() {
+				configurationType := stackConfiguration.ConfigurationType()
+				passwordProperties[configurationType] = append(passwordProperties[configurationType], stackConfiguration.StackConfigurationInfo.PropertyName)
+			}
+		}
+	}
+	log.Debugf("Return password properties: %v", passwordProperties)
+
+	return passwordProperties, nil
+}
@@ -0,0 +1,76 @@
+// This file permit to render configuration with variables and to merge configuration overlays
+// It's usefull to share the same configurations between several environments (dev, staging, prod)
+
+package client
+
+import (
+	"bytes"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"text/template"
+)
+
+const (
+	TEMPLATE_LEFT_DELIMITER  = "[["
+	TEMPLATE_RIGHT_DELIMITER = "]]"
+)
+
+// Render permit to get a copy of configuration where the property values are rendered as Go template with variables
+// The delimiters are [[ ]] because of Ambari use {{ }} for its own placeholders, like {{java64_home}} in *-env content
+// Sample of property value: `[[ .zookeeper_dir ]]/data`
+// The property values without delimiter are kept as is
+// It return error if the template is invalid or if a variable is missing
+func (c *Configuration) Render(variables map[string]interface{}) (*Configuration, error) {
+
+	configuration := *c
+	configuration.Properties = make(map[string]string, len(c.Properties))
+	for name, value := range c.Properties {
+		if strings.Contains(value, TEMPLATE_LEFT_DELIMITER) == false {
+			configuration.Properties[name] = value
+			continue
+		}
+		tmpl, err := template.New(name).Delims(TEMPLATE_LEFT_DELIMITER, TEMPLATE_RIGHT_DELIMITER).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, NewAmbariError(400, "Can't parse property %s/%s: %s", c.Type, name, err)
+		}
+		var buffer bytes.Buffer
+		err = tmpl.Execute(&buffer, variables)
+		if err != nil {
+			return nil, NewAmbariError(400, "Can't render property %s/%s: %s", c.Type, name, err)
+		}
+		configuration.Properties[name] = buffer.String()
+	}
+	log.Debugf("Return rendered configuration: %s", &configuration)
+
+	return &configuration, nil
+}
+
+// Merge permit to get a copy of configuration where the properties and properties attributes are overwritten by the overlay
+// The properties that not exist on overlay are kept
+func (c *Configuration) Merge(overlay *Configuration) *Configuration {
+
+	configuration := *c
+	configuration.Properties = make(map[string]string, len(c.Properties))
+	for name, value := range c.Properties {
+		configuration.Properties[name] = value
+	}
+	for name, value := range overlay.Properties {
+		configuration.Properties[name] = value
+	}
+
+	if c.PropertiesAttributes != nil || overlay.PropertiesAttributes != nil {
+		configuration.PropertiesAttributes = make(map[string]map[string]string)
+		for _, propertiesAttributes := range []map[string]map[string]string{c.PropertiesAttributes, overlay.PropertiesAttributes} {
+			for attribute, properties := range propertiesAttributes {
+				if configuration.PropertiesAttributes[attribute] == nil {
+					configuration.PropertiesAttributes[attribute] = make(map[string]string)
+				}
+				for name, value := range properties {
+					configuration.PropertiesAttributes[attribute][name] = value
+				}
+			}
+		}
+	}
+
+	return &configuration
+}
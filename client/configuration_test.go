@@ -0,0 +1,126 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestConfiguration() {
+
+	// Get desired configurations
+	configurations, err := s.client.DesiredConfigurations("test")
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), configurations)
+	zooCfg, isFound := configurations["zoo.cfg"]
+	assert.True(s.T(), isFound)
+	if isFound {
+		assert.Equal(s.T(), "zoo.cfg", zooCfg.Type)
+		assert.NotEmpty(s.T(), zooCfg.Properties)
+	}
+
+	// Get configuration
+	configuration, err := s.client.Configuration("test", "zoo.cfg", zooCfg.Tag)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), configuration)
+	if configuration != nil {
+		assert.Equal(s.T(), zooCfg.Properties, configuration.Properties)
+	}
+
+	// Get configuration that not exist
+	configuration, err = s.client.Configuration("test", "zoo.cfg", "fake")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), configuration)
+
+	// Diff configurations
+	current := &Configuration{
+		Type: "test",
+		Properties: map[string]string{
+			"a": "1",
+			"b": "2",
+			"c": "3",
+		},
+	}
+	target := &Configuration{
+		Type: "test",
+		Properties: map[string]string{
+			"a": "1",
+			"b": "20",
+			"d": "4",
+		},
+	}
+	changes := current.Diff(target)
+	assert.Equal(s.T(), []PropertyChange{
+		PropertyChange{Name: "b", Action: PROPERTY_UPDATED, OldValue: "2", NewValue: "20"},
+		PropertyChange{Name: "c", Action: PROPERTY_REMOVED, OldValue: "3"},
+		PropertyChange{Name: "d", Action: PROPERTY_ADDED, NewValue: "4"},
+	}, changes)
+	current = nil
+	changes = current.Diff(target)
+	assert.Equal(s.T(), 3, len(changes))
+
+	// Mask password properties
+	target.MarkPasswordProperties(map[string][]string{"test": []string{"b"}})
+	assert.True(s.T(), target.IsPasswordProperty("b"))
+	assert.False(s.T(), target.IsPasswordProperty("a"))
+	masked := target.Masked()
+	assert.Equal(s.T(), MASKED_VALUE, masked.Properties["b"])
+	assert.Equal(s.T(), "20", target.Properties["b"])
+	assert.NotContains(s.T(), target.String(), "20")
+	assert.Equal(s.T(), "null", current.String())
+	changes = current.Diff(target)
+	for _, change := range changes {
+		assert.Equal(s.T(), change.Name == "b", change.IsPassword)
+	}
+
+	// Render configuration
+	configuration = &Configuration{
+		Type: "test",
+		Properties: map[string]string{
+			"dir":  "[[ .root ]]/data",
+			"port": "2181",
+		},
+	}
+	rendered, err := configuration.Render(map[string]interface{}{"root": "/hadoop"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "/hadoop/data", rendered.Properties["dir"])
+	assert.Equal(s.T(), "2181", rendered.Properties["port"])
+	assert.Equal(s.T(), "[[ .root ]]/data", configuration.Properties["dir"])
+	_, err = configuration.Render(map[string]interface{}{})
+	assert.Error(s.T(), err)
+
+	// Render exported zookeeper-env, the Ambari placeholders are kept
+	zookeeperEnv := &Configuration{
+		Type: "zookeeper-env",
+		Properties: map[string]string{
+			"content":            "export JAVA_HOME={{java64_home}}\nexport ZOO_LOG_DIR={{zk_log_dir}}\n{% if security_enabled %}\nexport SERVER_JVMFLAGS=\"$SERVER_JVMFLAGS -Djava.security.auth.login.config={{zk_server_jaas_file}}\"\n{% endif %}",
+			"zk_log_dir":         "/var/log/zookeeper",
+			"zk_server_heapsize": "1024m",
+		},
+	}
+	rendered, err = zookeeperEnv.Render(map[string]interface{}{})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), zookeeperEnv.Properties, rendered.Properties)
+	rendered, err = zookeeperEnv.Render(map[string]interface{}{"root": "/hadoop"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), zookeeperEnv.Properties, rendered.Properties)
+
+	// Merge configuration
+	merged := configuration.Merge(&Configuration{
+		Type: "test",
+		Properties: map[string]string{
+			"port": "2182",
+		},
+		PropertiesAttributes: map[string]map[string]string{
+			"final": map[string]string{"port": "true"},
+		},
+	})
+	assert.Equal(s.T(), "[[ .root ]]/data", merged.Properties["dir"])
+	assert.Equal(s.T(), "2182", merged.Properties["port"])
+	assert.Equal(s.T(), "true", merged.PropertiesAttributes["final"]["port"])
+	assert.Equal(s.T(), "2181", configuration.Properties["port"])
+
+	// Get password properties
+	passwordProperties, err := s.client.PasswordProperties("test")
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), passwordProperties)
+
+}
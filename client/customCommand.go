@@ -0,0 +1,172 @@
+// This file permit to discover and run the custom commands provided by stack on services and components
+// Like REBALANCEHDFS on NAMENODE or DECOMMISSION on HBASE_MASTER
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+// CustomCommand describe one custom command available on service or on component
+// ComponentName is empty when the command is provided by the service
+type CustomCommand struct {
+	ServiceName   string `json:"service_name"`
+	ComponentName string `json:"component_name,omitempty"`
+	Command       string `json:"command"`
+}
+
+// String permit to get custom command object as Json string
+func (c *CustomCommand) String() string {
+	json, _ := json.Marshal(c)
+	return string(json)
+}
+
+// CustomCommands permit to get the custom commands available on service and on its components, from the stack of cluster
+// It return the list of custom commands
+// It return error if cluster or service not found or if something wrong when it call the API
+func (c *AmbariClient) CustomCommands(clusterName string, serviceName string) ([]CustomCommand, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ServiceName: ", serviceName)
+
+	cluster, err := c.Cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, NewAmbariError(404, "Cluster %s not found", clusterName)
+	}
+	stackName, stackVersion := cluster.StackNameAndVersion()
+
+	stackService, err := c.StackService(stackName, stackVersion, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if stackService == nil {
+		return nil, NewAmbariError(404, "Service %s not found in stack %s-%s", serviceName, stackName, stackVersion)
+	}
+	stackComponents, err := c.StackComponents(stackName, stackVersion, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	customCommands := make([]CustomCommand, 0)
+	for _, command := range stackService.StackServiceInfo.CustomCommands {
+		customCommands = append(customCommands, CustomCommand{
+			ServiceName: serviceName,
+			Command:     command,
+		})
+	}
+	for _, stackComponent := range stackComponents {
+		for _, command := range stackComponent.StackComponentInfo.CustomCommands {
+			customCommands = append(customCommands, CustomCommand{
+				ServiceName:   serviceName,
+				ComponentName: stackComponent.StackComponentInfo.ComponentName,
+				Command:       command,
+			})
+		}
+	}
+	log.Debugf("Return %d custom commands", len(customCommands))
+
+	return customCommands, nil
+}
+
+// ExecuteCustomCommand permit to run custom command on service or on component and wait the end of the request
+// If componentName is empty, the command is run on the service. If hostnames is empty, the command is run on all hosts of component.
+// The parameters are sent with the command, like the UI does (for example threshold for REBALANCEHDFS)
+// It return the request if all work fine
+// It return error if the command is not available, if the command failed or if something wrong when it call the API
+func (c *AmbariClient) ExecuteCustomCommand(clusterName string, serviceName string, componentName string, command string, hostnames []string, parameters map[string]string) (*RequestTask, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	if command == "" {
+		panic("Command can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ServiceName: ", serviceName)
+	log.Debug("ComponentName: ", componentName)
+	log.Debug("Command: ", command)
+	log.Debug("Hostnames: ", hostnames)
+	log.Debug("Parameters: ", parameters)
+
+	// Check the command is available
+	customCommands, err := c.CustomCommands(clusterName, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	isFound := false
+	for _, customCommand := range customCommands {
+		if customCommand.ComponentName == componentName && customCommand.Command == command {
+			isFound = true
+			break
+		}
+	}
+	if isFound == false {
+		if componentName != "" {
+			return nil, NewAmbariError(404, "Custom command %s not found on component %s of service %s", command, componentName, serviceName)
+		}
+		return nil, NewAmbariError(404, "Custom command %s not found on service %s", command, serviceName)
+	}
+
+	// Run the command
+	context := fmt.Sprintf("Execute %s on %s from API", command, serviceName)
+	operationLevel := &RequestOperationLevel{
+		Level:       OPERATION_LEVEL_SERVICE,
+		ClusterName: clusterName,
+		ServiceName: serviceName,
+	}
+	if componentName != "" {
+		context = fmt.Sprintf("Execute %s on %s from API", command, componentName)
+		if len(hostnames) == 1 {
+			operationLevel.Level = OPERATION_LEVEL_HOST_COMPONENT
+			operationLevel.Hostname = hostnames[0]
+		}
+	}
+	requestTask, err := c.SendRequest(clusterName, &Request{
+		RequestInfo: &RequestInfo{
+			Context:        context,
+			Command:        command,
+			Parameters:     parameters,
+			OperationLevel: operationLevel,
+		},
+		ResourceFilters: []RequestResourceFilter{
+			RequestResourceFilter{
+				ServiceName:   serviceName,
+				ComponentName: componentName,
+				Hosts:         strings.Join(hostnames, ","),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if requestTask == nil {
+		return nil, NewAmbariError(500, "Custom command %s is not created", command)
+	}
+
+	// Wait the end of the request
+	err = requestTask.Wait(c, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if requestTask.RequestTaskInfo.Status != REQUEST_COMPLETED {
+		return nil, requestTask.Error(c, clusterName)
+	}
+	log.Debugf("Return request: %s", requestTask)
+
+	return requestTask, nil
+}
@@ -0,0 +1,22 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestCustomCommand() {
+
+	// Get custom commands
+	customCommands, err := s.client.CustomCommands("test", "ZOOKEEPER")
+	assert.NoError(s.T(), err)
+	for _, customCommand := range customCommands {
+		assert.Equal(s.T(), "ZOOKEEPER", customCommand.ServiceName)
+		assert.NotEmpty(s.T(), customCommand.Command)
+	}
+	_, err = s.client.CustomCommands("test", "FAKE")
+	assert.Error(s.T(), err)
+
+	// Execute custom command that not exist
+	_, err = s.client.ExecuteCustomCommand("test", "ZOOKEEPER", "ZOOKEEPER_SERVER", "FAKE", nil, nil)
+	assert.Error(s.T(), err)
+}
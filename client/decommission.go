@@ -0,0 +1,472 @@
+// This file permit to decommission and recommission the slave components (DataNode, NodeManager and RegionServer)
+// It's needed to remove host without data unavailability
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	COMMAND_DECOMMISSION                  = "DECOMMISSION"
+	ADMIN_STATE_INSERVICE                 = "INSERVICE"
+	ADMIN_STATE_DECOMMISSIONED            = "DECOMMISSIONED"
+	DATANODE_DECOMMISSION_IN_PROGRESS     = "Decommission In Progress"
+	DEFAULT_DECOMMISSION_TIMEOUT          = 1 * time.Hour
+	DECOMMISSION_PARAMETER_SLAVE_TYPE     = "slave_type"
+	DECOMMISSION_PARAMETER_EXCLUDED_HOSTS = "excluded_hosts"
+	DECOMMISSION_PARAMETER_INCLUDED_HOSTS = "included_hosts"
+	DECOMMISSION_PARAMETER_MARK_DRAINING  = "mark_draining_only"
+	COMPONENT_DATANODE                    = "DATANODE"
+	COMPONENT_NODEMANAGER                 = "NODEMANAGER"
+	COMPONENT_HBASE_REGIONSERVER          = "HBASE_REGIONSERVER"
+)
+
+// decommissionMasters is the master component that run the decommission of each slave component
+var decommissionMasters = map[string]string{
+	COMPONENT_DATANODE:           "NAMENODE",
+	COMPONENT_NODEMANAGER:        "RESOURCEMANAGER",
+	COMPONENT_HBASE_REGIONSERVER: "HBASE_MASTER",
+}
+
+// IsDecommissionable permit to know if the component can be decommissioned
+func IsDecommissionable(componentName string) bool {
+	_, isFound := decommissionMasters[componentName]
+	return isFound
+}
+
+// Decommission permit to decommission slave component on hosts and wait the end, but no more than timeout
+// If timeout is 0, it wait without limit
+// It wait that NameNode has finished to replicate the blocks for DataNode, that ResourceManager report NodeManager as decommissioned
+// and that RegionServer has no more region
+// The hosts where the component is already decommissioned are skipped
+// It return error if component can't be decommissioned, if component not found on host, if the decommission failed or if something wrong when it call the API
+func (c *AmbariClient) Decommission(clusterName string, componentName string, hostnames []string, timeout time.Duration) error {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if componentName == "" {
+		panic("ComponentName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ComponentName: ", componentName)
+	log.Debug("Hostnames: ", hostnames)
+	log.Debug("Timeout: ", timeout)
+
+	hostComponents, err := c.decommissionHostComponents(clusterName, componentName, hostnames)
+	if err != nil {
+		return err
+	}
+	hostnamesToDecommission := make([]string, 0, len(hostComponents))
+	for _, hostComponent := range hostComponents {
+		if hostComponent.HostComponentInfo.DesiredAdminState == ADMIN_STATE_DECOMMISSIONED {
+			log.Infof("Component %s is already decommissioned on %s", componentName, hostComponent.HostComponentInfo.Hostname)
+			continue
+		}
+		hostnamesToDecommission = append(hostnamesToDecommission, hostComponent.HostComponentInfo.Hostname)
+	}
+	if len(hostnamesToDecommission) == 0 {
+		log.Debugf("There are no host to decommission")
+		return nil
+	}
+
+	parameters := map[string]string{
+		DECOMMISSION_PARAMETER_SLAVE_TYPE:     componentName,
+		DECOMMISSION_PARAMETER_EXCLUDED_HOSTS: strings.Join(hostnamesToDecommission, ","),
+	}
+	if componentName == COMPONENT_HBASE_REGIONSERVER {
+		parameters[DECOMMISSION_PARAMETER_MARK_DRAINING] = "false"
+	}
+	err = c.sendDecommissionCommand(clusterName, hostComponents[0].HostComponentInfo.ServiceName, componentName, parameters, fmt.Sprintf("Decommission %s on %s from API", componentName, strings.Join(hostnamesToDecommission, ",")))
+	if err != nil {
+		return err
+	}
+
+	// Wait the admin state
+	start := time.Now()
+	for {
+		isDecommissioned, err := c.isDecommissioned(clusterName, hostComponents[0].HostComponentInfo.ServiceName, componentName, hostnamesToDecommission)
+		if err != nil {
+			return err
+		}
+		if isDecommissioned == true {
+			break
+		}
+		if timeout > 0 && time.Since(start) > timeout {
+			return NewAmbariError(408, "Component %s is not decommissioned on %s after %s", componentName, strings.Join(hostnamesToDecommission, ","), timeout)
+		}
+		log.Debugf("Component %s is not yet decommissioned on %s", componentName, strings.Join(hostnamesToDecommission, ","))
+		time.Sleep(10 * time.Second)
+	}
+	log.Debugf("Component %s is decommissioned on %s", componentName, strings.Join(hostnamesToDecommission, ","))
+
+	return nil
+}
+
+// Recommission permit to put again in service slave component on hosts that was decommissioned
+// The hosts where the component is already in service are skipped
+// The component need to be started after to be used again
+// It return error if component can't be recommissioned, if component not found on host, if the recommission failed or if something wrong when it call the API
+func (c *AmbariClient) Recommission(clusterName string, componentName string, hostnames []string) error {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if componentName == "" {
+		panic("ComponentName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ComponentName: ", componentName)
+	log.Debug("Hostnames: ", hostnames)
+
+	hostComponents, err := c.decommissionHostComponents(clusterName, componentName, hostnames)
+	if err != nil {
+		return err
+	}
+	hostnamesToRecommission := make([]string, 0, len(hostComponents))
+	for _, hostComponent := range hostComponents {
+		if hostComponent.HostComponentInfo.DesiredAdminState != ADMIN_STATE_DECOMMISSIONED {
+			log.Infof("Component %s is already in service on %s", componentName, hostComponent.HostComponentInfo.Hostname)
+			continue
+		}
+		hostnamesToRecommission = append(hostnamesToRecommission, hostComponent.HostComponentInfo.Hostname)
+	}
+	if len(hostnamesToRecommission) == 0 {
+		log.Debugf("There are no host to recommission")
+		return nil
+	}
+
+	parameters := map[string]string{
+		DECOMMISSION_PARAMETER_SLAVE_TYPE:     componentName,
+		DECOMMISSION_PARAMETER_INCLUDED_HOSTS: strings.Join(hostnamesToRecommission, ","),
+	}
+	return c.sendDecommissionCommand(clusterName, hostComponents[0].HostComponentInfo.ServiceName, componentName, parameters, fmt.Sprintf("Recommission %s on %s from API", componentName, strings.Join(hostnamesToRecommission, ",")))
+}
+
+// RemoveHost permit to remove host from cluster in safe way
+// It decommission the slave components (DataNode, NodeManager and RegionServer) and wait the end, but no more than decommissionTimeout.
+// Then it stop and delete all components and finnaly delete the host.
+// It return error if host not found, if decommission failed or if something wrong when it call the API
+func (c *AmbariClient) RemoveHost(clusterName string, hostname string, decommissionTimeout time.Duration) error {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if hostname == "" {
+		panic("Hostname can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Hostname: ", hostname)
+	log.Debug("DecommissionTimeout: ", decommissionTimeout)
+
+	host, err := c.HostOnCluster(clusterName, hostname)
+	if err != nil {
+		return err
+	}
+	if host == nil {
+		return NewAmbariError(404, "Host %s not found in cluster %s", hostname, clusterName)
+	}
+
+	// Decommission the slave components
+	for _, hostComponent := range host.HostComponents {
+		if IsDecommissionable(hostComponent.HostComponentInfo.ComponentName) {
+			log.Infof("Decommission %s on %s", hostComponent.HostComponentInfo.ComponentName, hostname)
+			err = c.Decommission(clusterName, hostComponent.HostComponentInfo.ComponentName, []string{hostname}, decommissionTimeout)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Stop and delete all components
+	log.Infof("Stop all components on %s", hostname)
+	err = c.StopAllComponentsInHost(clusterName, hostname, false, true)
+	if err != nil {
+		return err
+	}
+	err = c.DeleteAllComponentsInHost(clusterName, hostname, true)
+	if err != nil {
+		return err
+	}
+
+	// Delete the host
+	log.Infof("Delete host %s", hostname)
+	return c.DeleteHost(clusterName, hostname)
+}
+
+// decommissionHostComponents permit to get the host components to decommission or recommission, with their admin state
+// It return error if component can't be decommissioned or if component not found on one host
+func (c *AmbariClient) decommissionHostComponents(clusterName string, componentName string, hostnames []string) ([]HostComponent, error) {
+
+	if IsDecommissionable(componentName) == false {
+		return nil, NewAmbariError(400, "Component %s can't be decommissioned", componentName)
+	}
+	if len(hostnames) == 0 {
+		return nil, NewAmbariError(400, "You need to set at least one host to decommission %s", componentName)
+	}
+
+	path := fmt.Sprintf("/clusters/%s/host_components", clusterName)
+	resp, err := c.Client().R().SetQueryParam("HostRoles/component_name", componentName).SetQueryParam("fields", "HostRoles/service_name,HostRoles/component_name,HostRoles/host_name,HostRoles/desired_admin_state").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	allHostComponents := &HostComponents{}
+	err = json.Unmarshal(resp.Body(), allHostComponents)
+	if err != nil {
+		return nil, err
+	}
+
+	hostComponentsByHost := make(map[string]HostComponent, len(allHostComponents.Items))
+	for _, hostComponent := range allHostComponents.Items {
+		hostComponentsByHost[hostComponent.HostComponentInfo.Hostname] = hostComponent
+	}
+	hostComponents := make([]HostComponent, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		hostComponent, isFound := hostComponentsByHost[hostname]
+		if isFound == false {
+			return nil, NewAmbariError(404, "Component %s not found on host %s", componentName, hostname)
+		}
+		hostComponents = append(hostComponents, hostComponent)
+	}
+
+	return hostComponents, nil
+}
+
+// sendDecommissionCommand permit to send the DECOMMISSION command on the master component and wait the end
+func (c *AmbariClient) sendDecommissionCommand(clusterName string, serviceName string, componentName string, parameters map[string]string, context string) error {
+
+	requestTask, err := c.SendRequest(clusterName, &Request{
+		RequestInfo: &RequestInfo{
+			Context:    context,
+			Command:    COMMAND_DECOMMISSION,
+			Parameters: parameters,
+			OperationLevel: &RequestOperationLevel{
+				Level:       OPERATION_LEVEL_SERVICE,
+				ClusterName: clusterName,
+				ServiceName: serviceName,
+			},
+		},
+		ResourceFilters: []RequestResourceFilter{
+			RequestResourceFilter{
+				ServiceName:   serviceName,
+				ComponentName: decommissionMasters[componentName],
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if requestTask == nil {
+		return nil
+	}
+
+	err = requestTask.Wait(c, clusterName)
+	if err != nil {
+		return err
+	}
+	if requestTask.RequestTaskInfo.Status != REQUEST_COMPLETED {
+		return requestTask.Error(c, clusterName)
+	}
+
+	return nil
+}
+
+// isDecommissioned permit to know if the component is decommissioned on all hosts
+// It check the desired admin state and the actual state: the DataNodes and the NodeManagers must be decommissioned on NameNode and ResourceManager,
+// the RegionServers must have no more region or be stopped. If the actual state is not yet reported, the decommission is not finished.
+// The DataNodes that are not alive (dead or unknown by NameNode) are considered as decommissioned.
+func (c *AmbariClient) isDecommissioned(clusterName string, serviceName string, componentName string, hostnames []string) (bool, error) {
+
+	hostComponents, err := c.decommissionHostComponents(clusterName, componentName, hostnames)
+	if err != nil {
+		return false, err
+	}
+	for _, hostComponent := range hostComponents {
+		if hostComponent.HostComponentInfo.DesiredAdminState != ADMIN_STATE_DECOMMISSIONED {
+			return false, nil
+		}
+	}
+
+	// Get the actual state
+	var states map[string]string
+	switch componentName {
+	case COMPONENT_DATANODE:
+		component, err := c.decommissionMaster(clusterName, serviceName, componentName, "metrics/dfs/namenode/LiveNodes")
+		if err != nil {
+			return false, err
+		}
+		states, err = component.LiveNodes()
+		if err != nil {
+			return false, err
+		}
+		// The dead DataNodes are only in DeadNodes, there are no more block to replicate from them
+		for _, hostname := range hostnames {
+			if _, isAlive := states[hostname]; states != nil && isAlive == false {
+				log.Infof("DataNode %s is not alive, it's considered as decommissioned", hostname)
+				states[hostname] = ADMIN_STATE_DECOMMISSIONED
+			}
+		}
+	case COMPONENT_NODEMANAGER:
+		component, err := c.decommissionMaster(clusterName, serviceName, componentName, "ServiceComponentInfo/rm_metrics/cluster/nodeManagers")
+		if err != nil {
+			return false, err
+		}
+		states, err = component.NodeManagers()
+		if err != nil {
+			return false, err
+		}
+	case COMPONENT_HBASE_REGIONSERVER:
+		states, err = c.regionServerStates(clusterName)
+		if err != nil {
+			return false, err
+		}
+	}
+	if states == nil {
+		log.Infof("The state of %s is not yet reported by %s", componentName, decommissionMasters[componentName])
+		return false, nil
+	}
+	inProgress := make([]string, 0)
+	for _, hostname := range hostnames {
+		if strings.ToUpper(states[hostname]) != ADMIN_STATE_DECOMMISSIONED {
+			inProgress = append(inProgress, hostname)
+		}
+	}
+	if len(inProgress) > 0 {
+		sort.Strings(inProgress)
+		log.Infof("Decommission of %s is in progress on %s", componentName, strings.Join(inProgress, ","))
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// decommissionMaster permit to get the master component that run the decommission with the given fields
+func (c *AmbariClient) decommissionMaster(clusterName string, serviceName string, componentName string, fields string) (*Component, error) {
+
+	path := fmt.Sprintf("/clusters/%s/services/%s/components/%s", clusterName, serviceName, decommissionMasters[componentName])
+	resp, err := c.Client().R().SetQueryParam("fields", fields).Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	component := &Component{}
+	err = json.Unmarshal(resp.Body(), component)
+	if err != nil {
+		return nil, err
+	}
+
+	return component, nil
+}
+
+// regionServerStates permit to get the decommission state of RegionServers
+// The RegionServer is decommissioned when it has no more region or when it's stopped
+func (c *AmbariClient) regionServerStates(clusterName string) (map[string]string, error) {
+
+	path := fmt.Sprintf("/clusters/%s/host_components", clusterName)
+	resp, err := c.Client().R().SetQueryParam("HostRoles/component_name", COMPONENT_HBASE_REGIONSERVER).SetQueryParam("fields", "HostRoles/host_name,HostRoles/state,metrics/hbase/regionserver/regions").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	hostComponents := &HostComponents{}
+	err = json.Unmarshal(resp.Body(), hostComponents)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]string, len(hostComponents.Items))
+	for _, hostComponent := range hostComponents.Items {
+		states[hostComponent.HostComponentInfo.Hostname] = hostComponent.RegionServerState()
+	}
+
+	return states, nil
+}
+
+// LiveNodes permit to get the admin state of each DataNode from the NameNode metrics
+// It return nil if the metrics are not available
+func (s *Component) LiveNodes() (map[string]string, error) {
+
+	dfs, _ := s.Metrics["dfs"].(map[string]interface{})
+	namenode, _ := dfs["namenode"].(map[string]interface{})
+	data, _ := namenode["LiveNodes"].(string)
+	if data == "" {
+		return nil, nil
+	}
+
+	liveNodes := make(map[string]map[string]interface{})
+	err := json.Unmarshal([]byte(data), &liveNodes)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]string, len(liveNodes))
+	for node, info := range liveNodes {
+		// The node can be hostname:port
+		hostname := strings.Split(node, ":")[0]
+		if adminState, isOk := info["adminState"].(string); isOk {
+			states[hostname] = adminState
+		}
+	}
+
+	return states, nil
+}
+
+// NodeManagers permit to get the state of each NodeManager from the ResourceManager metrics
+// It return nil if the metrics are not available
+func (s *Component) NodeManagers() (map[string]string, error) {
+
+	if s.ComponentInfo == nil {
+		return nil, nil
+	}
+	cluster, _ := s.ComponentInfo.RmMetrics["cluster"].(map[string]interface{})
+	data, _ := cluster["nodeManagers"].(string)
+	if data == "" {
+		return nil, nil
+	}
+
+	nodeManagers := make([]map[string]interface{}, 0)
+	err := json.Unmarshal([]byte(data), &nodeManagers)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]string, len(nodeManagers))
+	for _, info := range nodeManagers {
+		hostname, _ := info["HostName"].(string)
+		if state, isOk := info["State"].(string); isOk && hostname != "" {
+			states[hostname] = state
+		}
+	}
+
+	return states, nil
+}
+
+// RegionServerState permit to get the decommission state of RegionServer from its state and its number of regions
+// It return DECOMMISSIONED if the RegionServer is stopped or has no more region, else INSERVICE
+func (h *HostComponent) RegionServerState() string {
+
+	if h.HostComponentInfo != nil && h.HostComponentInfo.State != "" && h.HostComponentInfo.State != SERVICE_STARTED {
+		return ADMIN_STATE_DECOMMISSIONED
+	}
+	hbase, _ := h.Metrics["hbase"].(map[string]interface{})
+	regionServer, _ := hbase["regionserver"].(map[string]interface{})
+	if regions, isOk := regionServer["regions"].(float64); isOk && regions == 0 {
+		return ADMIN_STATE_DECOMMISSIONED
+	}
+
+	return ADMIN_STATE_INSERVICE
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestDecommission() {
+
+	// Check decommissionable components
+	assert.True(s.T(), IsDecommissionable(COMPONENT_DATANODE))
+	assert.True(s.T(), IsDecommissionable(COMPONENT_NODEMANAGER))
+	assert.False(s.T(), IsDecommissionable("ZOOKEEPER_SERVER"))
+
+	// Decommission component that can't be decommissioned
+	err := s.client.Decommission("test", "ZOOKEEPER_SERVER", []string{"ambari-agent"}, 0)
+	assert.Error(s.T(), err)
+
+	// Decommission component that not exist on host
+	err = s.client.Decommission("test", COMPONENT_DATANODE, []string{"fake"}, 0)
+	assert.Error(s.T(), err)
+	err = s.client.Recommission("test", COMPONENT_DATANODE, []string{"fake"})
+	assert.Error(s.T(), err)
+
+	// Remove host that not exist
+	err = s.client.RemoveHost("test", "fake", 0)
+	assert.Error(s.T(), err)
+
+	// Get DataNodes state from NameNode metrics
+	component := &Component{
+		Metrics: map[string]interface{}{
+			"dfs": map[string]interface{}{
+				"namenode": map[string]interface{}{
+					"LiveNodes": `{"worker01:50010":{"adminState":"Decommission In Progress"},"worker02:50010":{"adminState":"In Service"}}`,
+				},
+			},
+		},
+	}
+	liveNodes, err := component.LiveNodes()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), DATANODE_DECOMMISSION_IN_PROGRESS, liveNodes["worker01"])
+	assert.Equal(s.T(), "In Service", liveNodes["worker02"])
+	component = &Component{}
+	liveNodes, err = component.LiveNodes()
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), liveNodes)
+
+	// Get NodeManagers state from ResourceManager metrics
+	component = &Component{
+		ComponentInfo: &ComponentInfo{
+			RmMetrics: map[string]interface{}{
+				"cluster": map[string]interface{}{
+					"nodeManagers": `[{"HostName":"worker01","State":"DECOMMISSIONED"},{"HostName":"worker02","State":"RUNNING"}]`,
+				},
+			},
+		},
+	}
+	nodeManagers, err := component.NodeManagers()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), ADMIN_STATE_DECOMMISSIONED, nodeManagers["worker01"])
+	assert.Equal(s.T(), "RUNNING", nodeManagers["worker02"])
+	component = &Component{
+		ComponentInfo: &ComponentInfo{},
+	}
+	nodeManagers, err = component.NodeManagers()
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), nodeManagers)
+
+	// Get RegionServer state from its regions
+	hostComponent := &HostComponent{
+		HostComponentInfo: &HostComponentInfo{
+			State: SERVICE_STARTED,
+		},
+		Metrics: map[string]interface{}{
+			"hbase": map[string]interface{}{
+				"regionserver": map[string]interface{}{
+					"regions": float64(0),
+				},
+			},
+		},
+	}
+	assert.Equal(s.T(), ADMIN_STATE_DECOMMISSIONED, hostComponent.RegionServerState())
+	hostComponent.Metrics["hbase"].(map[string]interface{})["regionserver"].(map[string]interface{})["regions"] = float64(12)
+	assert.Equal(s.T(), ADMIN_STATE_INSERVICE, hostComponent.RegionServerState())
+	hostComponent.Metrics = nil
+	assert.Equal(s.T(), ADMIN_STATE_INSERVICE, hostComponent.RegionServerState())
+	hostComponent.HostComponentInfo.State = SERVICE_INSTALLED
+	assert.Equal(s.T(), ADMIN_STATE_DECOMMISSIONED, hostComponent.RegionServerState())
+}
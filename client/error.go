@@ -19,3 +19,21 @@ func NewAmbariError(code int, message string, params ...interface{}) AmbariError
 		Message: fmt.Sprintf(message, params...),
 	}
 }
+
+// ManualStepError is returned by the workflows when they need a manual action before to continue
+// You need to do the action and run again the workflow to resume it
+type ManualStepError struct {
+	Step    string
+	Message string
+}
+
+func (e ManualStepError) Error() string {
+	return e.Message
+}
+
+func NewManualStepError(step string, message string, params ...interface{}) ManualStepError {
+	return ManualStepError{
+		Step:    step,
+		Message: fmt.Sprintf(message, params...),
+	}
+}
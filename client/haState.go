@@ -0,0 +1,223 @@
+// This file permit to get the active / standby layout of master components with HA and to switch their roles by restarting the active one
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const (
+	HA_STATE_ACTIVE          = "ACTIVE"
+	HA_STATE_STANDBY         = "STANDBY"
+	HA_STATE_UNKNOWN         = "UNKNOWN"
+	COMPONENT_HBASE_MASTER   = "HBASE_MASTER"
+	DEFAULT_FAILOVER_TIMEOUT = 5 * time.Minute
+)
+
+// HaComponents are the master components that support HA with their service
+var HaComponents = map[string]string{
+	COMPONENT_NAMENODE:        "HDFS",
+	COMPONENT_RESOURCEMANAGER: "YARN",
+	COMPONENT_HBASE_MASTER:    "HBASE",
+}
+
+// HaState is the HA state of master component on host
+// State is ACTIVE, STANDBY or UNKNOWN when the state is not yet reported
+type HaState struct {
+	ComponentName string `json:"component_name"`
+	Hostname      string `json:"host_name"`
+	State         string `json:"state"`
+}
+
+// String permit to display the struct as JSON object
+func (h *HaState) String() string {
+	json, _ := json.Marshal(h)
+	return string(json)
+}
+
+// HaState permit to get the HA state of host component
+// Ambari report it on ha_state for ResourceManager and only on metrics for NameNode and HBase Master
+// It return HA_STATE_UNKNOWN if the state is not available
+func (h *HostComponent) HaState() string {
+
+	if h.HostComponentInfo != nil && h.HostComponentInfo.HaState != "" {
+		switch strings.ToUpper(h.HostComponentInfo.HaState) {
+		case HA_STATE_ACTIVE:
+			return HA_STATE_ACTIVE
+		case HA_STATE_STANDBY:
+			return HA_STATE_STANDBY
+		}
+	}
+
+	// NameNode
+	dfs, _ := h.Metrics["dfs"].(map[string]interface{})
+	fsNamesystem, _ := dfs["FSNamesystem"].(map[string]interface{})
+	if state, isOk := fsNamesystem["HAState"].(string); isOk {
+		switch strings.ToUpper(state) {
+		case HA_STATE_ACTIVE:
+			return HA_STATE_ACTIVE
+		case HA_STATE_STANDBY:
+			return HA_STATE_STANDBY
+		}
+	}
+
+	// HBase Master, the value can be string or boolean
+	hbase, _ := h.Metrics["hbase"].(map[string]interface{})
+	master, _ := hbase["master"].(map[string]interface{})
+	switch isActive := master["IsActiveMaster"].(type) {
+	case bool:
+		if isActive {
+			return HA_STATE_ACTIVE
+		}
+		return HA_STATE_STANDBY
+	case string:
+		if strings.ToLower(isActive) == "true" {
+			return HA_STATE_ACTIVE
+		} else if strings.ToLower(isActive) == "false" {
+			return HA_STATE_STANDBY
+		}
+	}
+
+	return HA_STATE_UNKNOWN
+}
+
+// HaStates permit to get the active / standby layout of master component
+// If componentName is empty, it return the layout of NameNode, ResourceManager and HBase Master
+// It return empty slice if the components are not in cluster
+// It return error if the component not support HA or if something wrong when it call the API
+func (c *AmbariClient) HaStates(clusterName string, componentName string) ([]HaState, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ComponentName: ", componentName)
+
+	componentNames := []string{COMPONENT_NAMENODE, COMPONENT_RESOURCEMANAGER, COMPONENT_HBASE_MASTER}
+	if componentName != "" {
+		if _, isFound := HaComponents[componentName]; isFound == false {
+			return nil, NewAmbariError(400, "Component %s not support HA", componentName)
+		}
+		componentNames = []string{componentName}
+	}
+
+	haStates := make([]HaState, 0)
+	path := fmt.Sprintf("/clusters/%s/host_components", clusterName)
+	for _, name := range componentNames {
+		resp, err := c.Client().R().SetQueryParam("HostRoles/component_name", name).SetQueryParam("fields", "HostRoles/host_name,HostRoles/ha_state,metrics/dfs/FSNamesystem/HAState,metrics/hbase/master/IsActiveMaster").Get(path)
+		if err != nil {
+			return nil, err
+		}
+		log.Debug("Response to get: ", resp)
+		if resp.StatusCode() >= 300 {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+		hostComponents := &HostComponents{}
+		err = json.Unmarshal(resp.Body(), hostComponents)
+		if err != nil {
+			return nil, err
+		}
+		for _, hostComponent := range hostComponents.Items {
+			haStates = append(haStates, HaState{
+				ComponentName: name,
+				Hostname:      hostComponent.HostComponentInfo.Hostname,
+				State:         hostComponent.HaState(),
+			})
+		}
+	}
+	log.Debugf("Return HA states: %+v", haStates)
+
+	return haStates, nil
+}
+
+// RestartActiveMaster permit to restart the active master component to let the automatic failover elect a standby one
+// Ambari not provide command to failover (it's done with `hdfs haadmin`, `yarn rmadmin` or by stopping the active HBase Master),
+// so the active component is stopped and started again. It's disruptive for the clients that are connected on it.
+// It wait until another host is active or the timeout is reached. If timeout is 0, it wait without limit
+// It return the new layout
+// It return error with the current layout if the restart failed or if the failover not work before the timeout
+// It return error if there are not one active and one standby component or if something wrong when it call the API
+func (c *AmbariClient) RestartActiveMaster(clusterName string, componentName string, timeout time.Duration) ([]HaState, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if componentName == "" {
+		panic("ComponentName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ComponentName: ", componentName)
+	log.Debug("Timeout: ", timeout)
+
+	haStates, err := c.HaStates(clusterName, componentName)
+	if err != nil {
+		return nil, err
+	}
+	activeHost, err := activeHaHost(componentName, haStates)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Restart %s on %s to failover", componentName, activeHost)
+	_, err = c.StopHostComponent(clusterName, activeHost, componentName)
+	if err == nil {
+		_, err = c.StartHostComponent(clusterName, activeHost, componentName)
+	}
+	if err != nil {
+		currentHaStates, errHaStates := c.HaStates(clusterName, componentName)
+		if errHaStates != nil {
+			log.Errorf("Can't get the HA states of %s: %s", componentName, errHaStates)
+		}
+		return currentHaStates, err
+	}
+
+	// Wait another host is active, the metrics are not updated immediately
+	start := time.Now()
+	for {
+		haStates, err = c.HaStates(clusterName, componentName)
+		if err != nil {
+			return nil, err
+		}
+		for _, haState := range haStates {
+			if haState.State == HA_STATE_ACTIVE && haState.Hostname != activeHost {
+				log.Debugf("Return HA states: %+v", haStates)
+				return haStates, nil
+			}
+		}
+		if timeout > 0 && time.Since(start) > timeout {
+			return haStates, NewAmbariError(408, "%s on %s is always active after %s", componentName, activeHost, timeout)
+		}
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// activeHaHost permit to get the host of the active component from the layout
+// It return error if there are not exactly one active component or if there are no standby component
+func activeHaHost(componentName string, haStates []HaState) (string, error) {
+
+	activeHost := ""
+	nbStandby := 0
+	for _, haState := range haStates {
+		switch haState.State {
+		case HA_STATE_ACTIVE:
+			if activeHost != "" {
+				return "", NewAmbariError(409, "There are more than one active %s: %s and %s", componentName, activeHost, haState.Hostname)
+			}
+			activeHost = haState.Hostname
+		case HA_STATE_STANDBY:
+			nbStandby++
+		}
+	}
+	if activeHost == "" {
+		return "", NewAmbariError(409, "There are no active %s", componentName)
+	}
+	if nbStandby == 0 {
+		return "", NewAmbariError(409, "There are no standby %s", componentName)
+	}
+
+	return activeHost, nil
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestHaStates() {
+
+	// Get all layouts
+	haStates, err := s.client.HaStates("test", "")
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), haStates)
+
+	// Component not support HA
+	_, err = s.client.HaStates("test", "ZOOKEEPER_SERVER")
+	assert.Error(s.T(), err)
+
+	// Restart without standby component
+	_, err = s.client.RestartActiveMaster("test", "NAMENODE", DEFAULT_FAILOVER_TIMEOUT)
+	assert.Error(s.T(), err)
+
+	// Restart component that not support HA
+	_, err = s.client.RestartActiveMaster("test", "ZOOKEEPER_SERVER", DEFAULT_FAILOVER_TIMEOUT)
+	assert.Error(s.T(), err)
+
+	// Get the active host from layout
+	activeHost, err := activeHaHost("NAMENODE", []HaState{
+		HaState{ComponentName: "NAMENODE", Hostname: "master01", State: HA_STATE_STANDBY},
+		HaState{ComponentName: "NAMENODE", Hostname: "master02", State: HA_STATE_ACTIVE},
+	})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "master02", activeHost)
+	_, err = activeHaHost("NAMENODE", []HaState{
+		HaState{ComponentName: "NAMENODE", Hostname: "master01", State: HA_STATE_ACTIVE},
+		HaState{ComponentName: "NAMENODE", Hostname: "master02", State: HA_STATE_ACTIVE},
+	})
+	assert.Error(s.T(), err)
+	_, err = activeHaHost("NAMENODE", []HaState{
+		HaState{ComponentName: "NAMENODE", Hostname: "master01", State: HA_STATE_STANDBY},
+		HaState{ComponentName: "NAMENODE", Hostname: "master02", State: HA_STATE_UNKNOWN},
+	})
+	assert.Error(s.T(), err)
+	_, err = activeHaHost("NAMENODE", []HaState{
+		HaState{ComponentName: "NAMENODE", Hostname: "master01", State: HA_STATE_ACTIVE},
+	})
+	assert.Error(s.T(), err)
+
+	// Read the state from ha_state and metrics
+	hostComponent := &HostComponent{
+		HostComponentInfo: &HostComponentInfo{
+			HaState: "ACTIVE",
+		},
+	}
+	assert.Equal(s.T(), HA_STATE_ACTIVE, hostComponent.HaState())
+	hostComponent = &HostComponent{
+		HostComponentInfo: &HostComponentInfo{},
+		Metrics: map[string]interface{}{
+			"dfs": map[string]interface{}{
+				"FSNamesystem": map[string]interface{}{
+					"HAState": "standby",
+				},
+			},
+		},
+	}
+	assert.Equal(s.T(), HA_STATE_STANDBY, hostComponent.HaState())
+	hostComponent = &HostComponent{
+		HostComponentInfo: &HostComponentInfo{},
+		Metrics: map[string]interface{}{
+			"hbase": map[string]interface{}{
+				"master": map[string]interface{}{
+					"IsActiveMaster": "true",
+				},
+			},
+		},
+	}
+	assert.Equal(s.T(), HA_STATE_ACTIVE, hostComponent.HaState())
+	hostComponent = &HostComponent{
+		HostComponentInfo: &HostComponentInfo{},
+	}
+	assert.Equal(s.T(), HA_STATE_UNKNOWN, hostComponent.HaState())
+}
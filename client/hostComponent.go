@@ -7,21 +7,30 @@ import (
 	"encoding/json"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
 )
 
 // Object that reflect the Ambari API
 type HostComponent struct {
-	HostComponentInfo *HostComponentInfo `json:"HostRoles"`
+	HostComponentInfo *HostComponentInfo     `json:"HostRoles"`
+	Metrics           map[string]interface{} `json:"metrics,omitempty"`
+}
+type HostComponents struct {
+	Items []HostComponent `json:"items,omitempty"`
 }
 type HostComponentInfo struct {
-	ClusterName   string                 `json:"cluster_name,omitempty"`
-	ComponentName string                 `json:"component_name,omitempty"`
-	Hostname      string                 `json:"host_name,omitempty"`
-	State         string                 `json:"state,omitempty"`
-	DesiredState  string                 `json:"desired_state,omitempty"`
-	ServiceName   string                 `json:"service_name,omitempty"`
-	HaState       string                 `json:"ha_state,omitempty"`
-	Metrics       map[string]interface{} `json:"metrics,omitempty"`
+	ClusterName       string                 `json:"cluster_name,omitempty"`
+	ComponentName     string                 `json:"component_name,omitempty"`
+	Hostname          string                 `json:"host_name,omitempty"`
+	State             string                 `json:"state,omitempty"`
+	DesiredState      string                 `json:"desired_state,omitempty"`
+	ServiceName       string                 `json:"service_name,omitempty"`
+	HaState           string                 `json:"ha_state,omitempty"`
+	MaintenanceState  string                 `json:"maintenance_state,omitempty"`
+	DesiredAdminState string                 `json:"desired_admin_state,omitempty"`
+	StaleConfigs      bool                   `json:"stale_configs,omitempty"`
+	Metrics           map[string]interface{} `json:"metrics,omitempty"`
 }
 
 func (h *HostComponent) CleanBeforeSave() {
@@ -423,3 +432,214 @@ func (c *AmbariClient) DeleteHostComponent(clusterName string, hostname string,
 	return nil
 
 }
+
+// HostComponentsQuery permit to select host components across hosts
+// The empty fields are not used to filter. Categories are MASTER, SLAVE or CLIENT.
+type HostComponentsQuery struct {
+	ServiceNames   []string `json:"service_names,omitempty"`
+	ComponentNames []string `json:"component_names,omitempty"`
+	Hostnames      []string `json:"host_names,omitempty"`
+	Categories     []string `json:"categories,omitempty"`
+}
+
+// String permit to display the struct as JSON object
+func (q *HostComponentsQuery) String() string {
+	json, _ := json.Marshal(q)
+	return string(json)
+}
+
+// IsEmpty permit to know if the query has no filter, so it will select all host components of cluster
+func (q *HostComponentsQuery) IsEmpty() bool {
+	return len(q.ServiceNames) == 0 && len(q.ComponentNames) == 0 && len(q.Hostnames) == 0 && len(q.Categories) == 0
+}
+
+// SetHostComponentsState permit to set the state (INSTALLED or STARTED) of all host components selected by query, in one request, and wait the end of the request
+// The categories are converted to the component names of cluster, because of host components have no category
+// The context is displayed on Ambari UI, if it's empty a default one is used
+// It return the request, or nil if there are no host component to update
+// It return error if the query is empty, if the request failed or if something wrong when it call the API
+func (c *AmbariClient) SetHostComponentsState(clusterName string, query *HostComponentsQuery, state string, context string) (*RequestTask, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if query == nil {
+		panic("Query can't be nil")
+	}
+	if state == "" {
+		panic("State can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debugf("Query: %s", query)
+	log.Debug("State: ", state)
+	log.Debug("Context: ", context)
+
+	// Empty query select all host components of cluster, it's too dangerous
+	if query.IsEmpty() {
+		return nil, NewAmbariError(400, "The query must have at least one filter")
+	}
+	if context == "" {
+		context = fmt.Sprintf("Set state %s on host components from API", state)
+	}
+
+	componentNames := query.ComponentNames
+	if len(query.Categories) > 0 {
+		components, err := c.ComponentsInCluster(clusterName, "")
+		if err != nil {
+			return nil, err
+		}
+		componentNames = filterComponentsByCategories(components, query.ComponentNames, query.Categories)
+		if len(componentNames) == 0 {
+			log.Debugf("There are no component with categories %s", strings.Join(query.Categories, ","))
+			return nil, nil
+		}
+	}
+
+	request := &Request{
+		RequestInfo: &RequestInfo{
+			Context: context,
+			Query:   hostComponentsPredicate(query.ServiceNames, componentNames, query.Hostnames),
+		},
+		Body: &HostComponent{
+			HostComponentInfo: &HostComponentInfo{
+				State: state,
+			},
+		},
+	}
+	log.Debugf("Request sended : %s", request)
+	path := fmt.Sprintf("/clusters/%s/host_components", clusterName)
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Client().R().SetBody(jsonData).Put(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to set state: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	if len(resp.Body()) == 0 {
+		log.Debugf("All host components are already %s", state)
+		return nil, nil
+	}
+	requestTask := &RequestTask{}
+	err = json.Unmarshal(resp.Body(), requestTask)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait the end of the request
+	err = requestTask.Wait(c, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if requestTask.RequestTaskInfo.Status != REQUEST_COMPLETED {
+		return nil, requestTask.Error(c, clusterName)
+	}
+	log.Debugf("Return request: %s", requestTask)
+
+	return requestTask, nil
+}
+
+// hostComponentsPredicate permit to get the Ambari predicate to select host components
+func hostComponentsPredicate(serviceNames []string, componentNames []string, hostnames []string) string {
+
+	predicates := make([]string, 0, 3)
+	if len(serviceNames) > 0 {
+		predicates = append(predicates, fmt.Sprintf("HostRoles/service_name.in(%s)", strings.Join(serviceNames, ",")))
+	}
+	if len(componentNames) > 0 {
+		predicates = append(predicates, fmt.Sprintf("HostRoles/component_name.in(%s)", strings.Join(componentNames, ",")))
+	}
+	if len(hostnames) > 0 {
+		predicates = append(predicates, fmt.Sprintf("HostRoles/host_name.in(%s)", strings.Join(hostnames, ",")))
+	}
+
+	return strings.Join(predicates, "&")
+}
+
+// filterComponentsByCategories permit to get the component names that are in categories
+// If componentNames is not empty, it keep only these components
+func filterComponentsByCategories(components []Component, componentNames []string, categories []string) []string {
+
+	selectedCategories := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		selectedCategories[category] = true
+	}
+	selectedComponents := make(map[string]bool, len(componentNames))
+	for _, componentName := range componentNames {
+		selectedComponents[componentName] = true
+	}
+
+	names := make([]string, 0, len(components))
+	for _, component := range components {
+		if selectedCategories[component.ComponentInfo.Category] == false {
+			continue
+		}
+		if len(componentNames) > 0 && selectedComponents[component.ComponentInfo.ComponentName] == false {
+			continue
+		}
+		names = append(names, component.ComponentInfo.ComponentName)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// componentHosts permit to get the hosts where the component is, sorted by name
+// It return error if something wrong when it call the API
+func (c *AmbariClient) componentHosts(clusterName string, componentName string) ([]string, error) {
+
+	path := fmt.Sprintf("/clusters/%s/host_components", clusterName)
+	resp, err := c.Client().R().SetQueryParam("HostRoles/component_name", componentName).SetQueryParam("fields", "HostRoles/host_name").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	hostComponents := &HostComponents{}
+	err = json.Unmarshal(resp.Body(), hostComponents)
+	if err != nil {
+		return nil, err
+	}
+	hostnames := make([]string, 0, len(hostComponents.Items))
+	for _, hostComponent := range hostComponents.Items {
+		hostnames = append(hostnames, hostComponent.HostComponentInfo.Hostname)
+	}
+	sort.Strings(hostnames)
+
+	return hostnames, nil
+}
+
+// installHostComponent permit to create the component on host if not exist and to install it
+// It return error if something wrong when it call the API
+func (c *AmbariClient) installHostComponent(clusterName string, serviceName string, componentName string, hostname string) error {
+
+	hostComponent, err := c.HostComponent(clusterName, hostname, componentName)
+	if err != nil {
+		return err
+	}
+	if hostComponent == nil {
+		_, err = c.CreateHostComponent(&HostComponent{
+			HostComponentInfo: &HostComponentInfo{
+				ClusterName:   clusterName,
+				ServiceName:   serviceName,
+				ComponentName: componentName,
+				Hostname:      hostname,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		log.Debugf("Component %s is created on host %s", componentName, hostname)
+	}
+
+	// Set the INSTALLED state install the component
+	_, err = c.StopHostComponent(clusterName, hostname, componentName)
+
+	return err
+}
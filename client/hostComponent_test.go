@@ -73,3 +73,47 @@ func (s *ClientTestSuite) TestHostComponent() {
 	assert.Nil(s.T(), hostComponent)
 
 }
+
+func (s *ClientTestSuite) TestHostComponentsState() {
+
+	// Stop the masters and slaves of ZooKeeper on all hosts
+	query := &HostComponentsQuery{
+		ServiceNames: []string{"ZOOKEEPER"},
+		Categories:   []string{COMPONENT_MASTER, COMPONENT_SLAVE},
+	}
+	_, err := s.client.SetHostComponentsState("test", query, SERVICE_STOPPED, "")
+	assert.NoError(s.T(), err)
+	hostComponent, err := s.client.HostComponent("test", "ambari-agent2", "ZOOKEEPER_SERVER")
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), hostComponent)
+	if hostComponent != nil {
+		assert.Equal(s.T(), SERVICE_STOPPED, hostComponent.HostComponentInfo.State)
+	}
+
+	// Start them
+	_, err = s.client.SetHostComponentsState("test", query, SERVICE_STARTED, "Start ZooKeeper from test")
+	assert.NoError(s.T(), err)
+	hostComponent, err = s.client.HostComponent("test", "ambari-agent2", "ZOOKEEPER_SERVER")
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), hostComponent)
+	if hostComponent != nil {
+		assert.Equal(s.T(), SERVICE_STARTED, hostComponent.HostComponentInfo.State)
+	}
+
+	// Empty query is refused
+	_, err = s.client.SetHostComponentsState("test", &HostComponentsQuery{}, SERVICE_STOPPED, "")
+	assert.Error(s.T(), err)
+
+	// Check the predicate
+	assert.Equal(s.T(), "HostRoles/component_name.in(DATANODE,NODEMANAGER)&HostRoles/host_name.in(worker01,worker02)", hostComponentsPredicate(nil, []string{"DATANODE", "NODEMANAGER"}, []string{"worker01", "worker02"}))
+	assert.Equal(s.T(), "", hostComponentsPredicate(nil, nil, nil))
+
+	// Check the categories
+	components := []Component{
+		Component{ComponentInfo: &ComponentInfo{ComponentName: "ZOOKEEPER_SERVER", Category: COMPONENT_MASTER}},
+		Component{ComponentInfo: &ComponentInfo{ComponentName: "ZOOKEEPER_CLIENT", Category: COMPONENT_CLIENT}},
+		Component{ComponentInfo: &ComponentInfo{ComponentName: "DATANODE", Category: COMPONENT_SLAVE}},
+	}
+	assert.Equal(s.T(), []string{"DATANODE", "ZOOKEEPER_SERVER"}, filterComponentsByCategories(components, nil, []string{COMPONENT_MASTER, COMPONENT_SLAVE}))
+	assert.Equal(s.T(), []string{"DATANODE"}, filterComponentsByCategories(components, []string{"DATANODE", "ZOOKEEPER_CLIENT"}, []string{COMPONENT_MASTER, COMPONENT_SLAVE}))
+}
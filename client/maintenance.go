@@ -0,0 +1,232 @@
+// This file permit to manage the maintenance state of services, hosts and host components
+// Ambari documentation: https://cwiki.apache.org/confluence/display/AMBARI/Maintenance+Mode
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+)
+
+// SetServiceMaintenance permit to set the maintenance state (ON or OFF) of service
+// It return the previous maintenance state, so you can restore it
+// It return error if service not found or if something wrong when it call the API
+func (c *AmbariClient) SetServiceMaintenance(clusterName string, serviceName string, state string) (string, error) {
+
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	services, err := c.SetServicesMaintenance(clusterName, fmt.Sprintf("ServiceInfo/service_name=%s", serviceName), state)
+	if err != nil {
+		return "", err
+	}
+	if len(services) == 0 {
+		return "", NewAmbariError(404, "Service %s not found in cluster %s", serviceName, clusterName)
+	}
+
+	return services[0].ServiceInfo.MaintenanceState, nil
+}
+
+// SetHostMaintenance permit to set the maintenance state (ON or OFF) of host
+// It return the previous maintenance state, so you can restore it
+// It return error if host not found or if something wrong when it call the API
+func (c *AmbariClient) SetHostMaintenance(clusterName string, hostname string, state string) (string, error) {
+
+	if hostname == "" {
+		panic("Hostname can't be empty")
+	}
+	hosts, err := c.SetHostsMaintenance(clusterName, fmt.Sprintf("Hosts/host_name=%s", hostname), state)
+	if err != nil {
+		return "", err
+	}
+	if len(hosts) == 0 {
+		return "", NewAmbariError(404, "Host %s not found in cluster %s", hostname, clusterName)
+	}
+
+	return hosts[0].HostInfo.MaintenanceState, nil
+}
+
+// SetHostComponentMaintenance permit to set the maintenance state (ON or OFF) of component on host
+// It return the previous maintenance state, so you can restore it
+// It return error if host component not found or if something wrong when it call the API
+func (c *AmbariClient) SetHostComponentMaintenance(clusterName string, hostname string, componentName string, state string) (string, error) {
+
+	if hostname == "" {
+		panic("Hostname can't be empty")
+	}
+	if componentName == "" {
+		panic("ComponentName can't be empty")
+	}
+	hostComponents, err := c.SetHostComponentsMaintenance(clusterName, fmt.Sprintf("HostRoles/host_name=%s&HostRoles/component_name=%s", hostname, componentName), state)
+	if err != nil {
+		return "", err
+	}
+	if len(hostComponents) == 0 {
+		return "", NewAmbariError(404, "Component %s not found on host %s", componentName, hostname)
+	}
+
+	return hostComponents[0].HostComponentInfo.MaintenanceState, nil
+}
+
+// SetServicesMaintenance permit to set the maintenance state (ON or OFF) of all services that match the predicate
+// The predicate use the Ambari query syntax, like `ServiceInfo/service_name.in(HDFS,YARN)`. If it's empty, all services are updated.
+// It return the services with their previous maintenance state, so you can restore them
+// It return error if something wrong when it call the API
+func (c *AmbariClient) SetServicesMaintenance(clusterName string, predicate string, state string) ([]Service, error) {
+
+	checkMaintenanceParameters(clusterName, state)
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Predicate: ", predicate)
+	log.Debug("State: ", state)
+
+	// Get the services with their current maintenance state
+	path := fmt.Sprintf("/clusters/%s/services", clusterName)
+	body, err := c.getMaintenanceResources(path, predicate, "ServiceInfo/service_name,ServiceInfo/maintenance_state")
+	if err != nil {
+		return nil, err
+	}
+	services := &Services{}
+	err = json.Unmarshal(body, services)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the maintenance state
+	if len(services.Items) > 0 {
+		err = c.putMaintenanceResources(path, predicate, "ServiceInfo", state, fmt.Sprintf("Turn %s maintenance mode for services from API", state))
+		if err != nil {
+			return nil, err
+		}
+	}
+	log.Debugf("Return %d services with previous maintenance state", len(services.Items))
+
+	return services.Items, nil
+}
+
+// SetHostsMaintenance permit to set the maintenance state (ON or OFF) of all hosts that match the predicate
+// The predicate use the Ambari query syntax, like `Hosts/host_name.in(worker01,worker02)`. If it's empty, all hosts are updated.
+// It return the hosts with their previous maintenance state, so you can restore them
+// It return error if something wrong when it call the API
+func (c *AmbariClient) SetHostsMaintenance(clusterName string, predicate string, state string) ([]Host, error) {
+
+	checkMaintenanceParameters(clusterName, state)
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Predicate: ", predicate)
+	log.Debug("State: ", state)
+
+	// Get the hosts with their current maintenance state
+	path := fmt.Sprintf("/clusters/%s/hosts", clusterName)
+	body, err := c.getMaintenanceResources(path, predicate, "Hosts/host_name,Hosts/maintenance_state")
+	if err != nil {
+		return nil, err
+	}
+	hosts := &Hosts{}
+	err = json.Unmarshal(body, hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the maintenance state
+	if len(hosts.Items) > 0 {
+		err = c.putMaintenanceResources(path, predicate, "Hosts", state, fmt.Sprintf("Turn %s maintenance mode for hosts from API", state))
+		if err != nil {
+			return nil, err
+		}
+	}
+	log.Debugf("Return %d hosts with previous maintenance state", len(hosts.Items))
+
+	return hosts.Items, nil
+}
+
+// SetHostComponentsMaintenance permit to set the maintenance state (ON or OFF) of all host components that match the predicate
+// The predicate use the Ambari query syntax, like `HostRoles/component_name=DATANODE&HostRoles/host_name.in(worker01,worker02)`. If it's empty, all host components are updated.
+// It return the host components with their previous maintenance state, so you can restore them
+// It return error if something wrong when it call the API
+func (c *AmbariClient) SetHostComponentsMaintenance(clusterName string, predicate string, state string) ([]HostComponent, error) {
+
+	checkMaintenanceParameters(clusterName, state)
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Predicate: ", predicate)
+	log.Debug("State: ", state)
+
+	// Get the host components with their current maintenance state
+	path := fmt.Sprintf("/clusters/%s/host_components", clusterName)
+	body, err := c.getMaintenanceResources(path, predicate, "HostRoles/host_name,HostRoles/component_name,HostRoles/service_name,HostRoles/maintenance_state")
+	if err != nil {
+		return nil, err
+	}
+	hostComponents := &HostComponents{}
+	err = json.Unmarshal(body, hostComponents)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the maintenance state
+	if len(hostComponents.Items) > 0 {
+		err = c.putMaintenanceResources(path, predicate, "HostRoles", state, fmt.Sprintf("Turn %s maintenance mode for host components from API", state))
+		if err != nil {
+			return nil, err
+		}
+	}
+	log.Debugf("Return %d host components with previous maintenance state", len(hostComponents.Items))
+
+	return hostComponents.Items, nil
+}
+
+func checkMaintenanceParameters(clusterName string, state string) {
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if state != MAINTENANCE_STATE_ON && state != MAINTENANCE_STATE_OFF {
+		panic("State must be ON or OFF")
+	}
+}
+
+// getMaintenanceResources permit to get the resources that match the predicate with the fields
+func (c *AmbariClient) getMaintenanceResources(path string, predicate string, fields string) ([]byte, error) {
+
+	if predicate != "" {
+		path = fmt.Sprintf("%s?%s", path, predicate)
+	}
+	resp, err := c.Client().R().SetQueryParam("fields", fields).Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+
+	return resp.Body(), nil
+}
+
+// putMaintenanceResources permit to set the maintenance state on resources that match the predicate
+func (c *AmbariClient) putMaintenanceResources(path string, predicate string, resourceName string, state string, context string) error {
+
+	request := &Request{
+		RequestInfo: &RequestInfo{
+			Context: context,
+			Query:   predicate,
+		},
+		Body: map[string]map[string]string{
+			resourceName: map[string]string{
+				"maintenance_state": state,
+			},
+		},
+	}
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Client().R().SetBody(jsonData).Put(path)
+	if err != nil {
+		return err
+	}
+	log.Debug("Response to update maintenance state: ", resp)
+	if resp.StatusCode() >= 300 {
+		return NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestMaintenance() {
+
+	// Set service maintenance
+	previousState, err := s.client.SetServiceMaintenance("test", "ZOOKEEPER", MAINTENANCE_STATE_ON)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), MAINTENANCE_STATE_OFF, previousState)
+	service, err := s.client.Service("test", "ZOOKEEPER")
+	assert.NoError(s.T(), err)
+	if service != nil {
+		assert.Equal(s.T(), MAINTENANCE_STATE_ON, service.ServiceInfo.MaintenanceState)
+	}
+	previousState, err = s.client.SetServiceMaintenance("test", "ZOOKEEPER", previousState)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), MAINTENANCE_STATE_ON, previousState)
+	_, err = s.client.SetServiceMaintenance("test", "FAKE", MAINTENANCE_STATE_ON)
+	assert.Error(s.T(), err)
+
+	// Set host maintenance
+	previousState, err = s.client.SetHostMaintenance("test", "ambari-agent2", MAINTENANCE_STATE_ON)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), MAINTENANCE_STATE_OFF, previousState)
+	previousState, err = s.client.SetHostMaintenance("test", "ambari-agent2", MAINTENANCE_STATE_OFF)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), MAINTENANCE_STATE_ON, previousState)
+
+	// Set host component maintenance
+	previousState, err = s.client.SetHostComponentMaintenance("test", "ambari-agent2", "ZOOKEEPER_CLIENT", MAINTENANCE_STATE_ON)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), MAINTENANCE_STATE_OFF, previousState)
+	previousState, err = s.client.SetHostComponentMaintenance("test", "ambari-agent2", "ZOOKEEPER_CLIENT", MAINTENANCE_STATE_OFF)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), MAINTENANCE_STATE_ON, previousState)
+
+	// Set hosts maintenance with predicate
+	hosts, err := s.client.SetHostsMaintenance("test", "Hosts/host_name.in(ambari-agent2,ambari-agent3)", MAINTENANCE_STATE_ON)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, len(hosts))
+	for _, host := range hosts {
+		_, err = s.client.SetHostMaintenance("test", host.HostInfo.Hostname, host.HostInfo.MaintenanceState)
+		assert.NoError(s.T(), err)
+	}
+}
@@ -0,0 +1,365 @@
+// This file permit to move master component (like NAMENODE or RESOURCEMANAGER) from one host to another, like the move master wizard of Ambari UI
+// Each step is saved in Ambari (persist API), so an interrupted move can be resumed by calling again MoveMasterComponent
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	MOVE_MASTER_STEP_CREATE             = "CREATE"
+	MOVE_MASTER_STEP_INSTALL            = "INSTALL"
+	MOVE_MASTER_STEP_STOP               = "STOP"
+	MOVE_MASTER_STEP_CONFIGURE          = "CONFIGURE"
+	MOVE_MASTER_STEP_DELETE             = "DELETE"
+	MOVE_MASTER_STEP_START              = "START"
+	MOVE_MASTER_STEP_RESTART_DEPENDENTS = "RESTART_DEPENDENTS"
+)
+
+// MoveMasterSteps is the ordered list of steps to move master component
+var MoveMasterSteps = []string{
+	MOVE_MASTER_STEP_CREATE,
+	MOVE_MASTER_STEP_INSTALL,
+	MOVE_MASTER_STEP_STOP,
+	MOVE_MASTER_STEP_CONFIGURE,
+	MOVE_MASTER_STEP_DELETE,
+	MOVE_MASTER_STEP_START,
+	MOVE_MASTER_STEP_RESTART_DEPENDENTS,
+}
+
+// MoveMasterProperties is the list of properties that contain the host of each master component, for each configuration type
+// The host is replaced by the new host in these properties when the master component is moved. You can add your own properties.
+var MoveMasterProperties = map[string]map[string][]string{
+	"NAMENODE": map[string][]string{
+		"core-site": []string{"fs.defaultFS"},
+		"hdfs-site": []string{"dfs.namenode.rpc-address", "dfs.namenode.http-address", "dfs.namenode.https-address", "dfs.namenode.servicerpc-address"},
+	},
+	"SECONDARY_NAMENODE": map[string][]string{
+		"hdfs-site": []string{"dfs.namenode.secondary.http-address"},
+	},
+	"RESOURCEMANAGER": map[string][]string{
+		"yarn-site": []string{"yarn.resourcemanager.hostname", "yarn.resourcemanager.address", "yarn.resourcemanager.admin.address", "yarn.resourcemanager.resource-tracker.address", "yarn.resourcemanager.scheduler.address", "yarn.resourcemanager.webapp.address", "yarn.resourcemanager.webapp.https.address"},
+	},
+	"APP_TIMELINE_SERVER": map[string][]string{
+		"yarn-site": []string{"yarn.timeline-service.address", "yarn.timeline-service.webapp.address", "yarn.timeline-service.webapp.https.address"},
+	},
+	"HISTORYSERVER": map[string][]string{
+		"mapred-site": []string{"mapreduce.jobhistory.address", "mapreduce.jobhistory.webapp.address", "mapreduce.jobhistory.webapp.https.address"},
+		"yarn-site":   []string{"yarn.log.server.url"},
+	},
+	"HIVE_METASTORE": map[string][]string{
+		"hive-site": []string{"hive.metastore.uris"},
+	},
+	"HIVE_SERVER": map[string][]string{
+		"hive-site": []string{"hive.server2.thrift.bind.host"},
+	},
+	"OOZIE_SERVER": map[string][]string{
+		"oozie-site": []string{"oozie.base.url"},
+	},
+}
+
+// MoveMasterCheckpoint keep the steps already done when move master component
+type MoveMasterCheckpoint struct {
+	Checkpoint
+	ClusterName   string `json:"cluster_name"`
+	ComponentName string `json:"component_name"`
+	FromHost      string `json:"from_host"`
+	ToHost        string `json:"to_host"`
+}
+
+// MoveMasterComponent permit to move master component from one host to another
+// It create and install the component on the new host, stop the old one, update the properties that contain the host (see MoveMasterProperties),
+// delete the old one, start the new one and finally restart the components with stale configs.
+// Each step done is saved, so if the move is interrupted, you can call it again with the same parameters to resume it.
+// For NAMENODE, it return ManualStepError before the start step: you need to copy the metadata (dfs.namenode.name.dir) on the new host
+// (or bootstrap it as standby with HA) and call it again to start the new NameNode.
+// It return error if component not found on old host, if other move of this component is in progress or if something wrong when it call the API
+func (c *AmbariClient) MoveMasterComponent(clusterName string, componentName string, fromHost string, toHost string) error {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if componentName == "" {
+		panic("ComponentName can't be empty")
+	}
+	if fromHost == "" {
+		panic("FromHost can't be empty")
+	}
+	if toHost == "" {
+		panic("ToHost can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ComponentName: ", componentName)
+	log.Debug("FromHost: ", fromHost)
+	log.Debug("ToHost: ", toHost)
+
+	if fromHost == toHost {
+		return NewAmbariError(400, "The new host of %s must be different of the old host", componentName)
+	}
+
+	// Load the checkpoint to resume the move
+	checkpoint, err := c.MoveMasterCheckpoint(clusterName, componentName)
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		checkpoint = &MoveMasterCheckpoint{
+			ClusterName:   clusterName,
+			ComponentName: componentName,
+			FromHost:      fromHost,
+			ToHost:        toHost,
+			Checkpoint: Checkpoint{
+				Steps: make([]string, 0, len(MoveMasterSteps)),
+			},
+		}
+	} else if checkpoint.FromHost != fromHost || checkpoint.ToHost != toHost {
+		return NewAmbariError(409, "Move of %s from %s to %s is in progress", componentName, checkpoint.FromHost, checkpoint.ToHost)
+	} else {
+		log.Infof("Resume move of %s from %s to %s, steps already done: %s", componentName, fromHost, toHost, strings.Join(checkpoint.Steps, ","))
+	}
+
+	// Get the old host component
+	var serviceName string
+	if checkpoint.IsDone(MOVE_MASTER_STEP_DELETE) == false {
+		hostComponent, err := c.HostComponent(clusterName, fromHost, componentName)
+		if err != nil {
+			return err
+		}
+		if hostComponent == nil {
+			return NewAmbariError(404, "Component %s not found on host %s in cluster %s", componentName, fromHost, clusterName)
+		}
+		serviceName = hostComponent.HostComponentInfo.ServiceName
+	}
+
+	for _, step := range MoveMasterSteps {
+		if checkpoint.IsDone(step) {
+			log.Debugf("Step %s is already done", step)
+			continue
+		}
+
+		// The NameNode metadata need to be copied on the new host before to start it, else it start with empty metadata.
+		// The manual step is done if the move is called again
+		if componentName == COMPONENT_NAMENODE && step == MOVE_MASTER_STEP_START && checkpoint.PendingStep != step {
+			checkpoint.PendingStep = step
+			err = c.saveCheckpoint(moveMasterCheckpointKey(clusterName, componentName), checkpoint)
+			if err != nil {
+				return err
+			}
+			return NewManualStepError(step, "Copy the NameNode metadata (dfs.namenode.name.dir) from %s to %s, or run `sudo su hdfs -l -c 'hdfs namenode -bootstrapStandby'` on %s if NameNode HA is enabled, then run again to resume", fromHost, toHost, toHost)
+		}
+		log.Infof("Move %s from %s to %s: %s", componentName, fromHost, toHost, step)
+
+		switch step {
+		case MOVE_MASTER_STEP_CREATE:
+			var hostComponent *HostComponent
+			hostComponent, err = c.HostComponent(clusterName, toHost, componentName)
+			if err == nil && hostComponent == nil {
+				_, err = c.CreateHostComponent(&HostComponent{
+					HostComponentInfo: &HostComponentInfo{
+						ClusterName:   clusterName,
+						ServiceName:   serviceName,
+						ComponentName: componentName,
+						Hostname:      toHost,
+					},
+				})
+			}
+		case MOVE_MASTER_STEP_INSTALL:
+			// Set the INSTALLED state install the component
+			_, err = c.StopHostComponent(clusterName, toHost, componentName)
+		case MOVE_MASTER_STEP_STOP:
+			_, err = c.StopHostComponent(clusterName, fromHost, componentName)
+		case MOVE_MASTER_STEP_CONFIGURE:
+			err = c.moveMasterConfigurations(clusterName, componentName, fromHost, toHost)
+		case MOVE_MASTER_STEP_DELETE:
+			err = c.DeleteHostComponent(clusterName, fromHost, componentName)
+		case MOVE_MASTER_STEP_START:
+			_, err = c.StartHostComponent(clusterName, toHost, componentName)
+		case MOVE_MASTER_STEP_RESTART_DEPENDENTS:
+			err = c.restartStaleComponents(clusterName, fmt.Sprintf("Restart components after move %s from API", componentName))
+		}
+		if err != nil {
+			return err
+		}
+
+		checkpoint.Done(step)
+		err = c.saveCheckpoint(moveMasterCheckpointKey(clusterName, componentName), checkpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The move is finished, remove the checkpoint
+	return c.deleteCheckpoint(moveMasterCheckpointKey(clusterName, componentName))
+}
+
+// MoveMasterCheckpoint permit to get the checkpoint of the move in progress of master component
+// It return nil if there are no move in progress
+// It return error if something wrong when it call the API
+func (c *AmbariClient) MoveMasterCheckpoint(clusterName string, componentName string) (*MoveMasterCheckpoint, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if componentName == "" {
+		panic("ComponentName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ComponentName: ", componentName)
+
+	checkpoint := &MoveMasterCheckpoint{}
+	isFound, err := c.loadCheckpoint(moveMasterCheckpointKey(clusterName, componentName), checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if isFound == false {
+		return nil, nil
+	}
+
+	return checkpoint, nil
+}
+
+func moveMasterCheckpointKey(clusterName string, componentName string) string {
+	return fmt.Sprintf("go-ambari-rest-move-master-%s-%s", clusterName, componentName)
+}
+
+// moveMasterConfigurations permit to replace the old host by the new host in the properties of master component
+// Only the configurations that change are saved, with new tag
+func (c *AmbariClient) moveMasterConfigurations(clusterName string, componentName string, fromHost string, toHost string) error {
+
+	properties, isFound := MoveMasterProperties[componentName]
+	if isFound == false {
+		log.Infof("There are no known property to update for %s", componentName)
+		return nil
+	}
+	configurations, err := c.DesiredConfigurations(clusterName)
+	if err != nil {
+		return err
+	}
+
+	tag := fmt.Sprintf("version_%s", time.Now().Format("2006-01-02_15:04:05"))
+	configurationTypes := make([]string, 0, len(properties))
+	for configurationType := range properties {
+		configurationTypes = append(configurationTypes, configurationType)
+	}
+	sort.Strings(configurationTypes)
+	for _, configurationType := range configurationTypes {
+		configuration, isFound := configurations[configurationType]
+		if isFound == false {
+			continue
+		}
+		isUpdated := false
+		newProperties := make(map[string]string, len(configuration.Properties))
+		for name, value := range configuration.Properties {
+			newProperties[name] = value
+		}
+		for _, name := range properties[configurationType] {
+			value, isFound := newProperties[name]
+			if isFound == false {
+				continue
+			}
+			newProperties[name] = replaceHost(value, fromHost, toHost)
+			if newProperties[name] == value {
+				continue
+			}
+			log.Infof("Update %s/%s: %s", configurationType, name, newProperties[name])
+			isUpdated = true
+		}
+		if isUpdated == false {
+			continue
+		}
+		configuration.Properties = newProperties
+		configuration.Tag = tag
+		_, err = c.CreateConfigurationOnCluster(clusterName, &configuration)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceHost permit to replace the host by the new host in property value, like host:port, URI or list of them
+// Only the whole hostname is replaced, so node1 is not replaced in node10 and calling it again do nothing
+func replaceHost(value string, fromHost string, toHost string) string {
+
+	isHostCharacter := func(character byte) bool {
+		return (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z') || (character >= '0' && character <= '9') || character == '.' || character == '-' || character == '_'
+	}
+
+	position := 0
+	for {
+		index := strings.Index(value[position:], fromHost)
+		if index < 0 {
+			return value
+		}
+		start := position + index
+		end := start + len(fromHost)
+		if (start == 0 || isHostCharacter(value[start-1]) == false) && (end == len(value) || isHostCharacter(value[end]) == false) {
+			value = value[:start] + toHost + value[end:]
+			position = start + len(toHost)
+		} else {
+			position = end
+		}
+	}
+}
+
+// restartStaleComponents permit to restart all components that have stale configs, except the clients
+func (c *AmbariClient) restartStaleComponents(clusterName string, context string) error {
+
+	path := fmt.Sprintf("/clusters/%s/host_components", clusterName)
+	resp, err := c.Client().R().SetQueryParam("HostRoles/stale_configs", "true").SetQueryParam("fields", "HostRoles/service_name,HostRoles/component_name,HostRoles/host_name").Get(path)
+	if err != nil {
+		return err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	hostComponents := &HostComponents{}
+	err = json.Unmarshal(resp.Body(), hostComponents)
+	if err != nil {
+		return err
+	}
+
+	// The clients can't be restarted
+	clientComponents, err := c.ComponentsInCluster(clusterName, COMPONENT_CLIENT)
+	if err != nil {
+		return err
+	}
+	isClient := make(map[string]bool, len(clientComponents))
+	for _, clientComponent := range clientComponents {
+		isClient[clientComponent.ComponentInfo.ComponentName] = true
+	}
+
+	// Group the hosts by component
+	hostnames := make(map[string][]string)
+	services := make(map[string]string)
+	for _, hostComponent := range hostComponents.Items {
+		componentName := hostComponent.HostComponentInfo.ComponentName
+		if isClient[componentName] {
+			continue
+		}
+		hostnames[componentName] = append(hostnames[componentName], hostComponent.HostComponentInfo.Hostname)
+		services[componentName] = hostComponent.HostComponentInfo.ServiceName
+	}
+	componentNames := make([]string, 0, len(hostnames))
+	for componentName := range hostnames {
+		componentNames = append(componentNames, componentName)
+	}
+	sort.Strings(componentNames)
+	for _, componentName := range componentNames {
+		log.Infof("Restart %s on %s", componentName, strings.Join(hostnames[componentName], ","))
+		err = c.restartComponents(clusterName, services[componentName], componentName, hostnames[componentName], context)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
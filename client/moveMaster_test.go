@@ -0,0 +1,39 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestMoveMasterComponent() {
+
+	// Move on same host
+	err := s.client.MoveMasterComponent("test", "ZOOKEEPER_SERVER", "ambari-agent", "ambari-agent")
+	assert.Error(s.T(), err)
+
+	// Move component that not exist on host
+	err = s.client.MoveMasterComponent("test", "NAMENODE", "fake", "ambari-agent")
+	assert.Error(s.T(), err)
+
+	// There are no move in progress
+	checkpoint, err := s.client.MoveMasterCheckpoint("test", "ZOOKEEPER_SERVER")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), checkpoint)
+
+	// Check the steps done
+	checkpoint = &MoveMasterCheckpoint{
+		Checkpoint: Checkpoint{
+			Steps: []string{MOVE_MASTER_STEP_CREATE, MOVE_MASTER_STEP_INSTALL},
+		},
+	}
+	assert.True(s.T(), checkpoint.IsDone(MOVE_MASTER_STEP_INSTALL))
+	assert.False(s.T(), checkpoint.IsDone(MOVE_MASTER_STEP_DELETE))
+
+	// Replace only the whole hostname
+	assert.Equal(s.T(), "node10:8020", replaceHost("node1:8020", "node1", "node10"))
+	assert.Equal(s.T(), "node10:8020", replaceHost("node10:8020", "node1", "node10"))
+	assert.Equal(s.T(), "hdfs://node2.local:8020", replaceHost("hdfs://node1.local:8020", "node1.local", "node2.local"))
+	assert.Equal(s.T(), "thrift://node2:9083,thrift://node11:9083", replaceHost("thrift://node1:9083,thrift://node11:9083", "node1", "node2"))
+	assert.Equal(s.T(), "http://node2:19888/jobhistory/logs", replaceHost("http://node1:19888/jobhistory/logs", "node1", "node2"))
+	assert.Equal(s.T(), "subnode1:8020", replaceHost("subnode1:8020", "node1", "node2"))
+	assert.Equal(s.T(), "node2", replaceHost("node1", "node1", "node2"))
+}
@@ -0,0 +1,391 @@
+// This file permit to enable the NameNode HA, like the wizard of Ambari UI
+// Some steps need to run HDFS commands on NameNode hosts, they can't be done with Ambari API. The workflow stop on these steps with ManualStepError,
+// you need to run the command and call again EnableNameNodeHA to resume it.
+// Ambari documentation: https://docs.hortonworks.com/HDPDocuments/Ambari-2.6.2.2/bk_ambari-operations/content/how_to_configure_namenode_high_availability.html
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const (
+	NAMENODE_HA_STEP_CREATE_CHECKPOINT         = "CREATE_CHECKPOINT"
+	NAMENODE_HA_STEP_STOP_SERVICES             = "STOP_SERVICES"
+	NAMENODE_HA_STEP_INSTALL_COMPONENTS        = "INSTALL_COMPONENTS"
+	NAMENODE_HA_STEP_CONFIGURE                 = "CONFIGURE"
+	NAMENODE_HA_STEP_START_JOURNALNODES        = "START_JOURNALNODES"
+	NAMENODE_HA_STEP_DELETE_SECONDARY_NAMENODE = "DELETE_SECONDARY_NAMENODE"
+	NAMENODE_HA_STEP_INITIALIZE_JOURNALNODES   = "INITIALIZE_JOURNALNODES"
+	NAMENODE_HA_STEP_START_NAMENODE            = "START_NAMENODE"
+	NAMENODE_HA_STEP_INITIALIZE_METADATA       = "INITIALIZE_METADATA"
+	NAMENODE_HA_STEP_START_ADDITIONAL_NAMENODE = "START_ADDITIONAL_NAMENODE"
+	NAMENODE_HA_STEP_START_SERVICES            = "START_SERVICES"
+	COMPONENT_NAMENODE                         = "NAMENODE"
+	COMPONENT_SECONDARY_NAMENODE               = "SECONDARY_NAMENODE"
+	COMPONENT_JOURNALNODE                      = "JOURNALNODE"
+	COMPONENT_ZKFC                             = "ZKFC"
+	COMPONENT_ZOOKEEPER_SERVER                 = "ZOOKEEPER_SERVER"
+)
+
+// NameNodeHASteps is the ordered list of steps to enable NameNode HA
+var NameNodeHASteps = []string{
+	NAMENODE_HA_STEP_CREATE_CHECKPOINT,
+	NAMENODE_HA_STEP_STOP_SERVICES,
+	NAMENODE_HA_STEP_INSTALL_COMPONENTS,
+	NAMENODE_HA_STEP_CONFIGURE,
+	NAMENODE_HA_STEP_START_JOURNALNODES,
+	NAMENODE_HA_STEP_DELETE_SECONDARY_NAMENODE,
+	NAMENODE_HA_STEP_INITIALIZE_JOURNALNODES,
+	NAMENODE_HA_STEP_START_NAMENODE,
+	NAMENODE_HA_STEP_INITIALIZE_METADATA,
+	NAMENODE_HA_STEP_START_ADDITIONAL_NAMENODE,
+	NAMENODE_HA_STEP_START_SERVICES,
+}
+
+// NameNodeHASpec describe the NameNode HA to enable
+// NameserviceId is the logical name of HDFS (fs.defaultFS will be hdfs://NameserviceId)
+// JournalNodeHosts need at least 3 hosts
+type NameNodeHASpec struct {
+	NameserviceId          string   `json:"nameservice_id"`
+	AdditionalNameNodeHost string   `json:"additional_namenode_host"`
+	JournalNodeHosts       []string `json:"journalnode_hosts"`
+}
+
+// NameNodeHACheckpoint keep the steps already done when enable NameNode HA
+type NameNodeHACheckpoint struct {
+	Checkpoint
+	ClusterName  string          `json:"cluster_name"`
+	NameNodeHost string          `json:"namenode_host"`
+	Spec         *NameNodeHASpec `json:"spec"`
+}
+
+// String permit to get spec object as Json string
+func (s *NameNodeHASpec) String() string {
+	json, _ := json.Marshal(s)
+	return string(json)
+}
+
+// EnableNameNodeHA permit to enable NameNode HA on cluster
+// It stop all services, install the JournalNodes, the additional NameNode and the ZKFCs, update hdfs-site and core-site, delete the Secondary NameNode and start all services.
+// Each step done is saved, so if the workflow is interrupted, you can call it again with the same spec to resume it.
+// It return ManualStepError when you need to run command on NameNode hosts. You need to run it and call again EnableNameNodeHA.
+// It return error if HA is already enabled, if spec is invalid or if something wrong when it call the API
+func (c *AmbariClient) EnableNameNodeHA(clusterName string, spec *NameNodeHASpec) error {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if spec == nil {
+		panic("Spec can't be nil")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debugf("Spec: %s", spec)
+
+	// Load the checkpoint to resume the workflow
+	checkpoint, err := c.NameNodeHACheckpoint(clusterName)
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		nameNodeHost, err := c.checkNameNodeHASpec(clusterName, spec)
+		if err != nil {
+			return err
+		}
+		checkpoint = &NameNodeHACheckpoint{
+			ClusterName:  clusterName,
+			NameNodeHost: nameNodeHost,
+			Spec:         spec,
+			Checkpoint: Checkpoint{
+				Steps: make([]string, 0, len(NameNodeHASteps)),
+			},
+		}
+	} else if checkpoint.Spec.NameserviceId != spec.NameserviceId || checkpoint.Spec.AdditionalNameNodeHost != spec.AdditionalNameNodeHost || strings.Join(checkpoint.Spec.JournalNodeHosts, ",") != strings.Join(spec.JournalNodeHosts, ",") {
+		return NewAmbariError(409, "Enable NameNode HA is already in progress with other spec: %s", checkpoint.Spec)
+	} else {
+		log.Infof("Resume enable NameNode HA, steps already done: %s", strings.Join(checkpoint.Steps, ","))
+	}
+	nameNodeHosts := []string{checkpoint.NameNodeHost, spec.AdditionalNameNodeHost}
+
+	for _, step := range NameNodeHASteps {
+		if checkpoint.IsDone(step) {
+			log.Debugf("Step %s is already done", step)
+			continue
+		}
+
+		// The manual step is done if the workflow is called again
+		if checkpoint.PendingStep != step {
+			var manualStepError error
+			switch step {
+			case NAMENODE_HA_STEP_CREATE_CHECKPOINT:
+				manualStepError = NewManualStepError(step, "Create a checkpoint on NameNode %s with `sudo su hdfs -l -c 'hdfs dfsadmin -safemode enter'` and `sudo su hdfs -l -c 'hdfs dfsadmin -saveNamespace'`, then run again to resume", checkpoint.NameNodeHost)
+			case NAMENODE_HA_STEP_INITIALIZE_JOURNALNODES:
+				manualStepError = NewManualStepError(step, "Initialize the JournalNodes on NameNode %s with `sudo su hdfs -l -c 'hdfs namenode -initializeSharedEdits'`, then run again to resume", checkpoint.NameNodeHost)
+			case NAMENODE_HA_STEP_INITIALIZE_METADATA:
+				manualStepError = NewManualStepError(step, "Initialize the metadata for NameNode automatic failover on %s with `sudo su hdfs -l -c 'hdfs zkfc -formatZK'` and on additional NameNode %s with `sudo su hdfs -l -c 'hdfs namenode -bootstrapStandby'`, then run again to resume", checkpoint.NameNodeHost, spec.AdditionalNameNodeHost)
+			}
+			if manualStepError != nil {
+				checkpoint.PendingStep = step
+				err = c.saveCheckpoint(nameNodeHACheckpointKey(clusterName), checkpoint)
+				if err != nil {
+					return err
+				}
+				return manualStepError
+			}
+		}
+		log.Infof("Enable NameNode HA: %s", step)
+
+		switch step {
+		case NAMENODE_HA_STEP_STOP_SERVICES:
+			err = c.StopServicesInOrder(clusterName, nil, false, false)
+		case NAMENODE_HA_STEP_INSTALL_COMPONENTS:
+			err = c.installHostComponent(clusterName, "HDFS", COMPONENT_NAMENODE, spec.AdditionalNameNodeHost)
+			for _, hostname := range spec.JournalNodeHosts {
+				if err == nil {
+					err = c.installHostComponent(clusterName, "HDFS", COMPONENT_JOURNALNODE, hostname)
+				}
+			}
+			for _, hostname := range nameNodeHosts {
+				if err == nil {
+					err = c.installHostComponent(clusterName, "HDFS", COMPONENT_ZKFC, hostname)
+				}
+			}
+		case NAMENODE_HA_STEP_CONFIGURE:
+			err = c.configureNameNodeHA(clusterName, checkpoint.NameNodeHost, spec)
+		case NAMENODE_HA_STEP_START_JOURNALNODES:
+			for _, hostname := range spec.JournalNodeHosts {
+				if err == nil {
+					_, err = c.StartHostComponent(clusterName, hostname, COMPONENT_JOURNALNODE)
+				}
+			}
+		case NAMENODE_HA_STEP_DELETE_SECONDARY_NAMENODE:
+			var hostnames []string
+			hostnames, err = c.componentHosts(clusterName, COMPONENT_SECONDARY_NAMENODE)
+			for _, hostname := range hostnames {
+				if err == nil {
+					err = c.DeleteHostComponent(clusterName, hostname, COMPONENT_SECONDARY_NAMENODE)
+				}
+			}
+		case NAMENODE_HA_STEP_START_NAMENODE:
+			_, err = c.StartService(clusterName, "ZOOKEEPER", false)
+			if err == nil {
+				_, err = c.StartHostComponent(clusterName, checkpoint.NameNodeHost, COMPONENT_NAMENODE)
+			}
+		case NAMENODE_HA_STEP_START_ADDITIONAL_NAMENODE:
+			_, err = c.StartHostComponent(clusterName, spec.AdditionalNameNodeHost, COMPONENT_NAMENODE)
+			for _, hostname := range nameNodeHosts {
+				if err == nil {
+					_, err = c.StartHostComponent(clusterName, hostname, COMPONENT_ZKFC)
+				}
+			}
+		case NAMENODE_HA_STEP_START_SERVICES:
+			err = c.StartServicesInOrder(clusterName, nil, false)
+		}
+		if err != nil {
+			return err
+		}
+
+		checkpoint.Done(step)
+		err = c.saveCheckpoint(nameNodeHACheckpointKey(clusterName), checkpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The workflow is finished, remove the checkpoint
+	return c.deleteCheckpoint(nameNodeHACheckpointKey(clusterName))
+}
+
+// NameNodeHACheckpoint permit to get the checkpoint of NameNode HA enabling in progress
+// It return nil if there are no workflow in progress
+// It return error if something wrong when it call the API
+func (c *AmbariClient) NameNodeHACheckpoint(clusterName string) (*NameNodeHACheckpoint, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+
+	checkpoint := &NameNodeHACheckpoint{}
+	isFound, err := c.loadCheckpoint(nameNodeHACheckpointKey(clusterName), checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if isFound == false {
+		return nil, nil
+	}
+
+	return checkpoint, nil
+}
+
+func nameNodeHACheckpointKey(clusterName string) string {
+	return fmt.Sprintf("go-ambari-rest-namenode-ha-%s", clusterName)
+}
+
+// checkNameNodeHASpec permit to check that NameNode HA can be enabled with the spec
+// It return the host of current NameNode
+func (c *AmbariClient) checkNameNodeHASpec(clusterName string, spec *NameNodeHASpec) (string, error) {
+
+	if spec.NameserviceId == "" {
+		return "", NewAmbariError(400, "You need to set the nameservice ID")
+	}
+	if spec.AdditionalNameNodeHost == "" {
+		return "", NewAmbariError(400, "You need to set the additional NameNode host")
+	}
+	if len(spec.JournalNodeHosts) < 3 {
+		return "", NewAmbariError(400, "You need at least 3 JournalNodes")
+	}
+
+	// Check the hosts before to stop anything on cluster
+	journalNodeHosts := make(map[string]bool, len(spec.JournalNodeHosts))
+	for _, hostname := range spec.JournalNodeHosts {
+		if hostname == "" {
+			return "", NewAmbariError(400, "The JournalNode host can't be empty")
+		}
+		if journalNodeHosts[hostname] {
+			return "", NewAmbariError(400, "The JournalNodes must be on different hosts, %s is set several times", hostname)
+		}
+		journalNodeHosts[hostname] = true
+	}
+	for _, hostname := range append([]string{spec.AdditionalNameNodeHost}, spec.JournalNodeHosts...) {
+		host, err := c.Host(hostname)
+		if err != nil {
+			return "", err
+		}
+		if host == nil {
+			return "", NewAmbariError(404, "Host %s not found", hostname)
+		}
+	}
+	configurations, err := c.DesiredConfigurations(clusterName)
+	if err != nil {
+		return "", err
+	}
+	if hdfsSite, isFound := configurations["hdfs-site"]; isFound && hdfsSite.Properties["dfs.nameservices"] != "" {
+		return "", NewAmbariError(409, "NameNode HA is already enabled with nameservice %s", hdfsSite.Properties["dfs.nameservices"])
+	}
+	nameNodeHosts, err := c.componentHosts(clusterName, COMPONENT_NAMENODE)
+	if err != nil {
+		return "", err
+	}
+	if len(nameNodeHosts) != 1 {
+		return "", NewAmbariError(400, "You need one NameNode to enable HA, there are %d", len(nameNodeHosts))
+	}
+	if nameNodeHosts[0] == spec.AdditionalNameNodeHost {
+		return "", NewAmbariError(400, "The additional NameNode must be on other host than %s", nameNodeHosts[0])
+	}
+	zookeeperHosts, err := c.componentHosts(clusterName, COMPONENT_ZOOKEEPER_SERVER)
+	if err != nil {
+		return "", err
+	}
+	if len(zookeeperHosts) == 0 {
+		return "", NewAmbariError(400, "You need ZooKeeper to enable NameNode HA")
+	}
+
+	return nameNodeHosts[0], nil
+}
+
+// configureNameNodeHA permit to update hdfs-site and core-site to enable NameNode HA
+func (c *AmbariClient) configureNameNodeHA(clusterName string, nameNodeHost string, spec *NameNodeHASpec) error {
+
+	configurations, err := c.DesiredConfigurations(clusterName)
+	if err != nil {
+		return err
+	}
+	zookeeperQuorum, err := c.zookeeperQuorum(clusterName, configurations)
+	if err != nil {
+		return err
+	}
+
+	hdfsSite := configurations["hdfs-site"]
+	coreSite := configurations["core-site"]
+	newHdfsSite, newCoreSite := NameNodeHAConfigurations(&hdfsSite, &coreSite, []string{nameNodeHost, spec.AdditionalNameNodeHost}, spec, zookeeperQuorum)
+
+	tag := fmt.Sprintf("version_%s", time.Now().Format("2006-01-02_15:04:05"))
+	for _, configuration := range []*Configuration{newHdfsSite, newCoreSite} {
+		configuration.Tag = tag
+		_, err = c.CreateConfigurationOnCluster(clusterName, configuration)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NameNodeHAConfigurations permit to compute hdfs-site and core-site with NameNode HA
+// The ports are kept from the current NameNode addresses
+func NameNodeHAConfigurations(hdfsSite *Configuration, coreSite *Configuration, nameNodeHosts []string, spec *NameNodeHASpec, zookeeperQuorum string) (*Configuration, *Configuration) {
+
+	newHdfsSite := hdfsSite.Merge(&Configuration{})
+	newCoreSite := coreSite.Merge(&Configuration{})
+	nameservice := spec.NameserviceId
+
+	// NameNode addresses
+	addresses := map[string]string{
+		"dfs.namenode.rpc-address":   "8020",
+		"dfs.namenode.http-address":  "50070",
+		"dfs.namenode.https-address": "50470",
+	}
+	nameNodeIds := make([]string, 0, len(nameNodeHosts))
+	for i := range nameNodeHosts {
+		nameNodeIds = append(nameNodeIds, fmt.Sprintf("nn%d", i+1))
+	}
+	for property, port := range addresses {
+		if value := hdfsSite.Properties[property]; strings.Contains(value, ":") {
+			port = value[strings.LastIndex(value, ":")+1:]
+		}
+		for i, hostname := range nameNodeHosts {
+			newHdfsSite.Properties[fmt.Sprintf("%s.%s.%s", property, nameservice, nameNodeIds[i])] = fmt.Sprintf("%s:%s", hostname, port)
+		}
+		delete(newHdfsSite.Properties, property)
+	}
+	delete(newHdfsSite.Properties, "dfs.namenode.secondary.http-address")
+
+	// JournalNodes
+	journalNodePort := "8485"
+	if value := hdfsSite.Properties["dfs.journalnode.rpc-address"]; strings.Contains(value, ":") {
+		journalNodePort = value[strings.LastIndex(value, ":")+1:]
+	}
+	journalNodes := make([]string, 0, len(spec.JournalNodeHosts))
+	for _, hostname := range spec.JournalNodeHosts {
+		journalNodes = append(journalNodes, fmt.Sprintf("%s:%s", hostname, journalNodePort))
+	}
+	if newHdfsSite.Properties["dfs.journalnode.edits.dir"] == "" {
+		newHdfsSite.Properties["dfs.journalnode.edits.dir"] = "/hadoop/hdfs/journal"
+	}
+
+	newHdfsSite.Properties["dfs.nameservices"] = nameservice
+	newHdfsSite.Properties[fmt.Sprintf("dfs.ha.namenodes.%s", nameservice)] = strings.Join(nameNodeIds, ",")
+	newHdfsSite.Properties["dfs.namenode.shared.edits.dir"] = fmt.Sprintf("qjournal://%s/%s", strings.Join(journalNodes, ";"), nameservice)
+	newHdfsSite.Properties[fmt.Sprintf("dfs.client.failover.proxy.provider.%s", nameservice)] = "org.apache.hadoop.hdfs.server.namenode.ha.ConfiguredFailoverProxyProvider"
+	newHdfsSite.Properties["dfs.ha.fencing.methods"] = "shell(/bin/true)"
+	newHdfsSite.Properties["dfs.ha.automatic-failover.enabled"] = "true"
+	newCoreSite.Properties["fs.defaultFS"] = fmt.Sprintf("hdfs://%s", nameservice)
+	newCoreSite.Properties["ha.zookeeper.quorum"] = zookeeperQuorum
+
+	return newHdfsSite, newCoreSite
+}
+
+// zookeeperQuorum permit to get the ZooKeeper servers of cluster, like host1:2181,host2:2181
+// The port is read from zoo.cfg
+func (c *AmbariClient) zookeeperQuorum(clusterName string, configurations map[string]Configuration) (string, error) {
+
+	zookeeperHosts, err := c.componentHosts(clusterName, COMPONENT_ZOOKEEPER_SERVER)
+	if err != nil {
+		return "", err
+	}
+	zookeeperPort := "2181"
+	if zooCfg, isFound := configurations["zoo.cfg"]; isFound && zooCfg.Properties["clientPort"] != "" {
+		zookeeperPort = zooCfg.Properties["clientPort"]
+	}
+	zookeeperQuorum := make([]string, 0, len(zookeeperHosts))
+	for _, hostname := range zookeeperHosts {
+		zookeeperQuorum = append(zookeeperQuorum, fmt.Sprintf("%s:%s", hostname, zookeeperPort))
+	}
+
+	return strings.Join(zookeeperQuorum, ","), nil
+}
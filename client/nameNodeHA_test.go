@@ -0,0 +1,81 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestEnableNameNodeHA() {
+
+	// Not enough JournalNodes
+	spec := &NameNodeHASpec{
+		NameserviceId:          "hdfscluster",
+		AdditionalNameNodeHost: "ambari-agent2",
+		JournalNodeHosts:       []string{"ambari-agent", "ambari-agent2"},
+	}
+	err := s.client.EnableNameNodeHA("test", spec)
+	assert.Error(s.T(), err)
+
+	// Additional NameNode not set
+	spec = &NameNodeHASpec{
+		NameserviceId:    "hdfscluster",
+		JournalNodeHosts: []string{"ambari-agent", "ambari-agent2", "ambari-agent3"},
+	}
+	err = s.client.EnableNameNodeHA("test", spec)
+	assert.Error(s.T(), err)
+
+	// JournalNode on host that not exist
+	spec = &NameNodeHASpec{
+		NameserviceId:          "hdfscluster",
+		AdditionalNameNodeHost: "ambari-agent2",
+		JournalNodeHosts:       []string{"ambari-agent2", "ambari-agent3", "fake"},
+	}
+	err = s.client.EnableNameNodeHA("test", spec)
+	assert.Error(s.T(), err)
+	if ambariError, ok := err.(AmbariError); ok {
+		assert.Equal(s.T(), 404, ambariError.Code)
+	}
+
+	// There are no workflow in progress
+	checkpoint, err := s.client.NameNodeHACheckpoint("test")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), checkpoint)
+
+	// Check the configurations
+	hdfsSite := &Configuration{
+		Type: "hdfs-site",
+		Properties: map[string]string{
+			"dfs.namenode.rpc-address":            "master01:8021",
+			"dfs.namenode.http-address":           "master01:50070",
+			"dfs.namenode.secondary.http-address": "master02:50090",
+			"dfs.journalnode.edits.dir":           "/data/journal",
+			"dfs.replication":                     "3",
+		},
+	}
+	coreSite := &Configuration{
+		Type: "core-site",
+		Properties: map[string]string{
+			"fs.defaultFS": "hdfs://master01:8021",
+		},
+	}
+	spec = &NameNodeHASpec{
+		NameserviceId:          "hdfscluster",
+		AdditionalNameNodeHost: "master02",
+		JournalNodeHosts:       []string{"master01", "master02", "master03"},
+	}
+	newHdfsSite, newCoreSite := NameNodeHAConfigurations(hdfsSite, coreSite, []string{"master01", "master02"}, spec, "master01:2181")
+	assert.Equal(s.T(), "hdfscluster", newHdfsSite.Properties["dfs.nameservices"])
+	assert.Equal(s.T(), "nn1,nn2", newHdfsSite.Properties["dfs.ha.namenodes.hdfscluster"])
+	assert.Equal(s.T(), "master01:8021", newHdfsSite.Properties["dfs.namenode.rpc-address.hdfscluster.nn1"])
+	assert.Equal(s.T(), "master02:8021", newHdfsSite.Properties["dfs.namenode.rpc-address.hdfscluster.nn2"])
+	assert.Equal(s.T(), "master02:50470", newHdfsSite.Properties["dfs.namenode.https-address.hdfscluster.nn2"])
+	assert.Equal(s.T(), "qjournal://master01:8485;master02:8485;master03:8485/hdfscluster", newHdfsSite.Properties["dfs.namenode.shared.edits.dir"])
+	assert.Equal(s.T(), "/data/journal", newHdfsSite.Properties["dfs.journalnode.edits.dir"])
+	assert.Equal(s.T(), "3", newHdfsSite.Properties["dfs.replication"])
+	assert.NotContains(s.T(), newHdfsSite.Properties, "dfs.namenode.rpc-address")
+	assert.NotContains(s.T(), newHdfsSite.Properties, "dfs.namenode.secondary.http-address")
+	assert.Equal(s.T(), "hdfs://hdfscluster", newCoreSite.Properties["fs.defaultFS"])
+	assert.Equal(s.T(), "master01:2181", newCoreSite.Properties["ha.zookeeper.quorum"])
+
+	// The current configurations are not updated
+	assert.Equal(s.T(), "master01:8021", hdfsSite.Properties["dfs.namenode.rpc-address"])
+}
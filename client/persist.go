@@ -0,0 +1,115 @@
+// This file permit to save key value in Ambari with the persist API, like the Ambari UI does to keep the state of wizards
+// It's used to save the checkpoints of long workflows, so they can be resumed
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+// Checkpoint keep the steps already done of workflow that can be resumed
+// PendingStep is the manual step that the user need to do before resume the workflow
+type Checkpoint struct {
+	Steps       []string `json:"steps"`
+	PendingStep string   `json:"pending_step,omitempty"`
+}
+
+// IsDone permit to know if the step is already done
+func (c *Checkpoint) IsDone(step string) bool {
+	for _, stepDone := range c.Steps {
+		if stepDone == step {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Done permit to mark the step as done, it remove the pending step
+func (c *Checkpoint) Done(step string) {
+	c.Steps = append(c.Steps, step)
+	c.PendingStep = ""
+}
+
+// loadCheckpoint permit to read the checkpoint saved with the persist API in the given struct
+// It return false if there are no checkpoint
+func (c *AmbariClient) loadCheckpoint(key string, checkpoint interface{}) (bool, error) {
+
+	data, err := c.persistedValue(key)
+	if err != nil {
+		return false, err
+	}
+	if data == nil {
+		return false, nil
+	}
+	err = json.Unmarshal(data, checkpoint)
+	if err != nil {
+		return false, err
+	}
+	log.Debugf("Return checkpoint: %+v", checkpoint)
+
+	return true, nil
+}
+
+// saveCheckpoint permit to save the checkpoint with the persist API
+func (c *AmbariClient) saveCheckpoint(key string, checkpoint interface{}) error {
+
+	jsonData, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	return c.persist(key, string(jsonData))
+}
+
+// deleteCheckpoint permit to remove the checkpoint when the workflow is finished
+func (c *AmbariClient) deleteCheckpoint(key string) error {
+	return c.persist(key, "")
+}
+
+// persist permit to save key value in Ambari
+// The persist API can't delete key, so set empty value to remove it
+func (c *AmbariClient) persist(key string, value string) error {
+
+	jsonData, err := json.Marshal(map[string]string{key: value})
+	if err != nil {
+		return err
+	}
+	resp, err := c.Client().R().SetBody(jsonData).Post("/persist")
+	if err != nil {
+		return err
+	}
+	log.Debug("Response to persist: ", resp)
+	if resp.StatusCode() >= 300 {
+		return NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+
+	return nil
+}
+
+// persistedValue permit to get the value saved in Ambari
+// It return nil if the key not exist or if the value is empty
+func (c *AmbariClient) persistedValue(key string) ([]byte, error) {
+
+	path := fmt.Sprintf("/persist/%s", key)
+	resp, err := c.Client().R().Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			return nil, nil
+		} else {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+	}
+	if len(strings.TrimSpace(string(resp.Body()))) == 0 {
+		return nil, nil
+	}
+
+	return resp.Body(), nil
+}
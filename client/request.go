@@ -1,17 +1,47 @@
+// This file permit to send action request in Ambari API, like custom command on components
+// Ambari documentation: https://github.com/apache/ambari/blob/trunk/ambari-server/docs/api/v1/request-resources.md
+
 package client
 
 import (
 	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	COMMAND_RESTART                = "RESTART"
+	OPERATION_LEVEL_SERVICE        = "SERVICE"
+	OPERATION_LEVEL_HOST_COMPONENT = "HOST_COMPONENT"
 )
 
 type Request struct {
-	RequestInfo *RequestInfo `json:"RequestInfo"`
-	Body        interface{}  `json:"Body"`
+	RequestInfo     *RequestInfo            `json:"RequestInfo"`
+	Body            interface{}             `json:"Body,omitempty"`
+	ResourceFilters []RequestResourceFilter `json:"Requests/resource_filters,omitempty"`
 }
 
 type RequestInfo struct {
-	Context string `json:"context"`
-	Query   string `json:"query,omitempty"`
+	Context        string                 `json:"context"`
+	Query          string                 `json:"query,omitempty"`
+	Command        string                 `json:"command,omitempty"`
+	Parameters     map[string]string      `json:"parameters,omitempty"`
+	OperationLevel *RequestOperationLevel `json:"operation_level,omitempty"`
+}
+
+type RequestOperationLevel struct {
+	Level       string `json:"level"`
+	ClusterName string `json:"cluster_name"`
+	ServiceName string `json:"service_name,omitempty"`
+	Hostname    string `json:"host_name,omitempty"`
+}
+
+// RequestResourceFilter permit to select the components where to run the command
+// Hosts is the list of hosts separated by comma
+type RequestResourceFilter struct {
+	ServiceName   string `json:"service_name,omitempty"`
+	ComponentName string `json:"component_name,omitempty"`
+	Hosts         string `json:"hosts,omitempty"`
 }
 
 // String permit to get request object as Json string
@@ -19,3 +49,44 @@ func (r *Request) String() string {
 	json, _ := json.Marshal(r)
 	return string(json)
 }
+
+// SendRequest permit to send action request (custom command or action) on cluster
+// It return RequestTask if all work fine
+// It return nil if no request is created
+// It return error if something wrong when it call the API
+func (c *AmbariClient) SendRequest(clusterName string, request *Request) (*RequestTask, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if request == nil {
+		panic("Request can't be nil")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Request: ", request)
+
+	path := fmt.Sprintf("/clusters/%s/requests", clusterName)
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Client().R().SetBody(jsonData).Post(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response when send request: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	if len(resp.Body()) == 0 {
+		return nil, nil
+	}
+	requestTask := &RequestTask{}
+	err = json.Unmarshal(resp.Body(), requestTask)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return request: %s", requestTask)
+
+	return requestTask, nil
+}
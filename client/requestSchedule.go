@@ -0,0 +1,478 @@
+// This file permit to manage request schedules in Ambari API. A request schedule run several requests in batches, with a pause between them.
+// Ambari use it for the rolling restart.
+// Ambari documentation: https://cwiki.apache.org/confluence/display/AMBARI/Request+Schedules
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"net/url"
+	"time"
+)
+
+const (
+	REQUEST_SCHEDULE_SCHEDULED = "SCHEDULED"
+	REQUEST_SCHEDULE_COMPLETED = "COMPLETED"
+	REQUEST_SCHEDULE_ABORTED   = "ABORTED"
+	REQUEST_SCHEDULE_DISABLED  = "DISABLED"
+	REQUEST_SCHEDULE_PAUSED    = "PAUSED"
+	BATCH_REQUEST_POST         = "POST"
+)
+
+// Request schedule object, as returned by Ambari
+type RequestSchedule struct {
+	RequestScheduleInfo *RequestScheduleInfo `json:"RequestSchedule"`
+}
+type RequestSchedules struct {
+	Items []RequestSchedule `json:"items,omitempty"`
+}
+type RequestScheduleInfo struct {
+	Id                  int                   `json:"id,omitempty"`
+	ClusterName         string                `json:"cluster_name,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Status              string                `json:"status,omitempty"`
+	LastExecutionStatus string                `json:"last_execution_status,omitempty"`
+	Creator             string                `json:"create_user,omitempty"`
+	CreateTime          string                `json:"create_time,omitempty"`
+	UpdateTime          string                `json:"update_time,omitempty"`
+	Batch               *RequestScheduleBatch `json:"batch,omitempty"`
+	Schedule            *Schedule             `json:"schedule,omitempty"`
+}
+type RequestScheduleBatch struct {
+	BatchRequests []BatchRequest `json:"batch_requests,omitempty"`
+	BatchSettings *BatchSettings `json:"batch_settings,omitempty"`
+}
+type BatchRequest struct {
+	OrderId       int    `json:"order_id"`
+	RequestId     int    `json:"request_id,omitempty"`
+	Type          string `json:"request_type,omitempty"`
+	Uri           string `json:"request_uri,omitempty"`
+	Body          string `json:"request_body,omitempty"`
+	Status        string `json:"request_status,omitempty"`
+	ReturnCode    int    `json:"return_code,omitempty"`
+	ReturnMessage string `json:"return_message,omitempty"`
+}
+type BatchSettings struct {
+	BatchSeparationInSeconds int `json:"batch_separation_in_seconds"`
+	TaskFailureTolerance     int `json:"task_failure_tolerance_limit"`
+}
+
+// Schedule permit to run the request schedule at given time, with cron like fields
+// If the request schedule has no schedule, it's run as soon as it's created
+type Schedule struct {
+	Minutes     string `json:"minutes,omitempty"`
+	Hours       string `json:"hours,omitempty"`
+	DaysOfMonth string `json:"days_of_month,omitempty"`
+	Month       string `json:"month,omitempty"`
+	DayOfWeek   string `json:"day_of_week,omitempty"`
+	Year        string `json:"year,omitempty"`
+	StartTime   string `json:"startTime,omitempty"`
+	EndTime     string `json:"endTime,omitempty"`
+}
+
+// RequestScheduleStatus is the status of request schedule computed from its batches and from the requests run by them
+type RequestScheduleStatus struct {
+	Id              int     `json:"id"`
+	Status          string  `json:"status"`
+	BatchCount      int     `json:"batch_count"`
+	FinishedBatch   int     `json:"finished_batch_count"`
+	FailedBatch     int     `json:"failed_batch_count"`
+	TaskCount       int     `json:"task_count"`
+	CompletedTask   int     `json:"completed_task_count"`
+	FailedTask      int     `json:"failed_task_count"`
+	AbordedTask     int     `json:"aborted_task_count"`
+	ProgressPercent float64 `json:"progress_percent"`
+}
+
+// Request schedule spec, used to create request schedule. Ambari don't use the same format when it return the request schedule.
+type RequestScheduleSpec struct {
+	RequestScheduleSpecInfo *RequestScheduleSpecInfo `json:"RequestSchedule"`
+}
+type RequestScheduleSpecInfo struct {
+	Description string      `json:"description,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	Batch       []BatchSpec `json:"batch,omitempty"`
+	Schedule    *Schedule   `json:"schedule,omitempty"`
+}
+type BatchSpec struct {
+	Requests      []BatchRequestSpec `json:"requests,omitempty"`
+	BatchSettings *BatchSettingsSpec `json:"batch_settings,omitempty"`
+}
+type BatchRequestSpec struct {
+	OrderId int      `json:"order_id"`
+	Type    string   `json:"type"`
+	Uri     string   `json:"uri"`
+	Body    *Request `json:"RequestBodyInfo,omitempty"`
+}
+type BatchSettingsSpec struct {
+	BatchSeparationInSeconds     int `json:"batch_separation_in_seconds"`
+	TaskFailureTolerance         int `json:"task_failure_tolerance"`
+	TaskFailureTolerancePerBatch int `json:"task_failure_tolerance_per_batch,omitempty"`
+}
+
+// Response when create request schedule
+type requestScheduleResponse struct {
+	Resources []RequestSchedule `json:"resources"`
+}
+
+// String permit to get request schedule object as Json string
+func (r *RequestSchedule) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// String permit to get request schedule spec object as Json string
+func (r *RequestScheduleSpec) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// String permit to get request schedule status object as Json string
+func (r *RequestScheduleStatus) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// IsFinished permit to know if the request schedule is finished
+// The request schedule is finished when it's not more scheduled (completed, aborted or disabled)
+func (r *RequestSchedule) IsFinished() bool {
+	return r.RequestScheduleInfo.Status != REQUEST_SCHEDULE_SCHEDULED
+}
+
+// FinishedBatchRequests permit to get the number of batch requests that are finished
+func (r *RequestSchedule) FinishedBatchRequests() int {
+	nb := 0
+	if r.RequestScheduleInfo.Batch != nil {
+		for _, batchRequest := range r.RequestScheduleInfo.Batch.BatchRequests {
+			if batchRequest.IsFinished() {
+				nb++
+			}
+		}
+	}
+
+	return nb
+}
+
+// FailedBatchRequests permit to get the batch requests that are finished without success
+func (r *RequestSchedule) FailedBatchRequests() []BatchRequest {
+	batchRequests := make([]BatchRequest, 0)
+	if r.RequestScheduleInfo.Batch != nil {
+		for _, batchRequest := range r.RequestScheduleInfo.Batch.BatchRequests {
+			if batchRequest.IsFinished() && batchRequest.Status != REQUEST_COMPLETED {
+				batchRequests = append(batchRequests, batchRequest)
+			}
+		}
+	}
+
+	return batchRequests
+}
+
+// IsFinished permit to know if the request of batch is finished
+func (b *BatchRequest) IsFinished() bool {
+	switch b.Status {
+	case REQUEST_COMPLETED, REQUEST_FAILED, TASK_ABORTED, TASK_TIMEDOUT:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait permit to wait the request schedule is finished
+// It log the progress each time a batch is finished
+// It return error if API call failed
+func (r *RequestSchedule) Wait(c *AmbariClient, clusterName string) error {
+	return r.WaitWithTimeout(c, clusterName, 0)
+}
+
+// WaitWithTimeout permit to wait the request schedule is finished, but no more than timeout
+// If timeout is 0, it wait without limit
+// It log the progress each time a batch is finished
+// It return error if API call failed or if the request schedule is not finished before the timeout
+func (r *RequestSchedule) WaitWithTimeout(c *AmbariClient, clusterName string, timeout time.Duration) error {
+
+	start := time.Now()
+	nbFinished := 0
+	for {
+		requestSchedule, err := c.RequestSchedule(clusterName, r.RequestScheduleInfo.Id)
+		if err != nil {
+			return err
+		}
+		if requestSchedule == nil {
+			return NewAmbariError(404, "Request schedule %d not found", r.RequestScheduleInfo.Id)
+		}
+		*r = *requestSchedule
+
+		nbBatchRequests := 0
+		if r.RequestScheduleInfo.Batch != nil {
+			nbBatchRequests = len(r.RequestScheduleInfo.Batch.BatchRequests)
+		}
+		if r.FinishedBatchRequests() != nbFinished {
+			nbFinished = r.FinishedBatchRequests()
+			log.Infof("Request schedule '%s' (%d): %d/%d batches are finished", r.RequestScheduleInfo.Description, r.RequestScheduleInfo.Id, nbFinished, nbBatchRequests)
+		}
+		if r.IsFinished() || (nbBatchRequests > 0 && nbFinished == nbBatchRequests) {
+			break
+		}
+		if timeout > 0 && time.Since(start) > timeout {
+			return NewAmbariError(408, "Request schedule %d is not finished after %s, %d/%d batches are finished", r.RequestScheduleInfo.Id, timeout, nbFinished, nbBatchRequests)
+		}
+		log.Debugf("Request schedule '%s' (%d) is not yet finished, state is %s", r.RequestScheduleInfo.Description, r.RequestScheduleInfo.Id, r.RequestScheduleInfo.Status)
+		time.Sleep(10 * time.Second)
+	}
+	log.Debugf("Request schedule '%s' (%d) is finished with state %s", r.RequestScheduleInfo.Description, r.RequestScheduleInfo.Id, r.RequestScheduleInfo.Status)
+
+	return nil
+}
+
+// requestsUri permit to get the uri used by batch request to send request on cluster
+// Ambari call itself with this uri, so it need the API prefix (like /api/v1)
+func (c *AmbariClient) requestsUri(clusterName string) string {
+	apiPrefix := ""
+	baseUrl, err := url.Parse(c.Client().HostURL)
+	if err == nil {
+		apiPrefix = baseUrl.Path
+	}
+
+	return fmt.Sprintf("%s/clusters/%s/requests", apiPrefix, clusterName)
+}
+
+// CreateRequestSchedule permit to create new request schedule on cluster
+// Ambari start to run the batches as soon as the request schedule is created
+// It return the request schedule if all work fine
+// It return error if something wrong when it call the API
+func (c *AmbariClient) CreateRequestSchedule(clusterName string, spec *RequestScheduleSpec) (*RequestSchedule, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if spec == nil {
+		panic("Spec can't be nil")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Spec: ", spec)
+
+	path := fmt.Sprintf("/clusters/%s/request_schedules", clusterName)
+	jsonData, err := json.Marshal([]*RequestScheduleSpec{spec})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Client().R().SetBody(jsonData).Post(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to create: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	response := &requestScheduleResponse{}
+	err = json.Unmarshal(resp.Body(), response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Resources) == 0 || response.Resources[0].RequestScheduleInfo == nil {
+		return nil, NewAmbariError(500, "Ambari don't return the request schedule that just created")
+	}
+
+	return c.RequestSchedule(clusterName, response.Resources[0].RequestScheduleInfo.Id)
+}
+
+// RequestSchedule permit to get request schedule with its batches
+// It return the request schedule if found
+// It return nil if request schedule not found
+// It return error if something wrong when it call the API
+func (c *AmbariClient) RequestSchedule(clusterName string, id int) (*RequestSchedule, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Id: ", id)
+
+	path := fmt.Sprintf("/clusters/%s/request_schedules/%d", clusterName, id)
+	resp, err := c.Client().R().Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			return nil, nil
+		} else {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+	}
+	requestSchedule := &RequestSchedule{}
+	err = json.Unmarshal(resp.Body(), requestSchedule)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return request schedule: %s", requestSchedule)
+
+	return requestSchedule, nil
+}
+
+// RequestSchedules permit to get all request schedules of cluster
+// If status is not empty, it return only the request schedules with this status (SCHEDULED, COMPLETED, ...)
+// It return the list of request schedules
+// It return error if something wrong when it call the API
+func (c *AmbariClient) RequestSchedules(clusterName string, status string) ([]RequestSchedule, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Status: ", status)
+
+	path := fmt.Sprintf("/clusters/%s/request_schedules", clusterName)
+	request := c.Client().R().SetQueryParam("fields", "RequestSchedule/*")
+	if status != "" {
+		request.SetQueryParam("RequestSchedule/status", status)
+	}
+	resp, err := request.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	requestSchedules := &RequestSchedules{}
+	err = json.Unmarshal(resp.Body(), requestSchedules)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return %d request schedules", len(requestSchedules.Items))
+
+	return requestSchedules.Items, nil
+}
+
+// UpdateRequestSchedule permit to update existing request schedule, like its description, its schedule or its status
+// It return the request schedule if all work fine
+// It return error if something wrong when it call the API
+func (c *AmbariClient) UpdateRequestSchedule(clusterName string, id int, spec *RequestScheduleSpec) (*RequestSchedule, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if spec == nil {
+		panic("Spec can't be nil")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Id: ", id)
+	log.Debug("Spec: ", spec)
+
+	path := fmt.Sprintf("/clusters/%s/request_schedules/%d", clusterName, id)
+	jsonData, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Client().R().SetBody(jsonData).Put(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to update: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+
+	return c.RequestSchedule(clusterName, id)
+}
+
+// DeleteRequestSchedule permit to delete request schedule
+// Ambari don't remove it, it's disabled, so the batches not yet run will never run
+// It return error if something wrong when it call the API
+func (c *AmbariClient) DeleteRequestSchedule(clusterName string, id int) error {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Id: ", id)
+
+	path := fmt.Sprintf("/clusters/%s/request_schedules/%d", clusterName, id)
+	resp, err := c.Client().R().Delete(path)
+	if err != nil {
+		return err
+	}
+	log.Debug("Response to delete: ", resp)
+	if resp.StatusCode() >= 300 {
+		return NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+
+	return nil
+}
+
+// Requests permit to get the requests run by the batches of request schedule
+// The batches not yet run are skipped
+// It return error if something wrong when it call the API
+func (r *RequestSchedule) Requests(c *AmbariClient, clusterName string) ([]RequestTask, error) {
+
+	requestTasks := make([]RequestTask, 0)
+	if r.RequestScheduleInfo.Batch == nil {
+		return requestTasks, nil
+	}
+	for _, batchRequest := range r.RequestScheduleInfo.Batch.BatchRequests {
+		if batchRequest.RequestId == 0 {
+			continue
+		}
+		requestTask, err := c.Request(clusterName, batchRequest.RequestId)
+		if err != nil {
+			return nil, err
+		}
+		if requestTask != nil {
+			requestTasks = append(requestTasks, *requestTask)
+		}
+	}
+
+	return requestTasks, nil
+}
+
+// RequestScheduleStatus permit to get the status of request schedule with the progress of the requests run by its batches
+// The progress is the average of progress of all batches (the batches not yet run have 0 %)
+// It return nil if request schedule not found
+// It return error if something wrong when it call the API
+func (c *AmbariClient) RequestScheduleStatus(clusterName string, id int) (*RequestScheduleStatus, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("Id: ", id)
+
+	requestSchedule, err := c.RequestSchedule(clusterName, id)
+	if err != nil {
+		return nil, err
+	}
+	if requestSchedule == nil {
+		return nil, nil
+	}
+	requestTasks, err := requestSchedule.Requests(c, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &RequestScheduleStatus{
+		Id:            id,
+		Status:        requestSchedule.RequestScheduleInfo.Status,
+		FinishedBatch: requestSchedule.FinishedBatchRequests(),
+		FailedBatch:   len(requestSchedule.FailedBatchRequests()),
+	}
+	if requestSchedule.RequestScheduleInfo.Batch != nil {
+		status.BatchCount = len(requestSchedule.RequestScheduleInfo.Batch.BatchRequests)
+	}
+	for _, requestTask := range requestTasks {
+		status.TaskCount += requestTask.RequestTaskInfo.TaskCount
+		status.CompletedTask += requestTask.RequestTaskInfo.CompletedTask
+		status.FailedTask += requestTask.RequestTaskInfo.FailedTask
+		status.AbordedTask += requestTask.RequestTaskInfo.AbordedTask
+		status.ProgressPercent += requestTask.RequestTaskInfo.ProgressPercent
+	}
+	if status.BatchCount > 0 {
+		status.ProgressPercent = status.ProgressPercent / float64(status.BatchCount)
+	}
+	log.Debugf("Return request schedule status: %s", status)
+
+	return status, nil
+}
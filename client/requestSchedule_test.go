@@ -0,0 +1,93 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"time"
+)
+
+func (s *ClientTestSuite) TestRequestSchedule() {
+
+	// Create request schedule that run the service check
+	spec := &RequestScheduleSpec{
+		RequestScheduleSpecInfo: &RequestScheduleSpecInfo{
+			Description: "Test request schedule",
+			Batch: []BatchSpec{
+				BatchSpec{
+					Requests: []BatchRequestSpec{
+						BatchRequestSpec{
+							OrderId: 1,
+							Type:    BATCH_REQUEST_POST,
+							Uri:     s.client.requestsUri("test"),
+							Body: &Request{
+								RequestInfo: &RequestInfo{
+									Context: "Test request schedule",
+									Command: ServiceCheckCommand("ZOOKEEPER"),
+								},
+								ResourceFilters: []RequestResourceFilter{
+									RequestResourceFilter{
+										ServiceName: "ZOOKEEPER",
+									},
+								},
+							},
+						},
+					},
+				},
+				BatchSpec{
+					BatchSettings: &BatchSettingsSpec{
+						BatchSeparationInSeconds: 1,
+					},
+				},
+			},
+		},
+	}
+	requestSchedule, err := s.client.CreateRequestSchedule("test", spec)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), requestSchedule)
+	if requestSchedule != nil {
+		assert.Equal(s.T(), "Test request schedule", requestSchedule.RequestScheduleInfo.Description)
+
+		// Wait and get status
+		err = requestSchedule.WaitWithTimeout(s.client, "test", 10*time.Minute)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), 1, requestSchedule.FinishedBatchRequests())
+		status, err := s.client.RequestScheduleStatus("test", requestSchedule.RequestScheduleInfo.Id)
+		assert.NoError(s.T(), err)
+		assert.NotNil(s.T(), status)
+		if status != nil {
+			assert.Equal(s.T(), 1, status.BatchCount)
+			assert.Equal(s.T(), 0, status.FailedBatch)
+			assert.True(s.T(), status.TaskCount > 0)
+		}
+
+		// List request schedules
+		requestSchedules, err := s.client.RequestSchedules("test", "")
+		assert.NoError(s.T(), err)
+		assert.NotEmpty(s.T(), requestSchedules)
+
+		// Delete request schedule
+		err = s.client.DeleteRequestSchedule("test", requestSchedule.RequestScheduleInfo.Id)
+		assert.NoError(s.T(), err)
+	}
+
+	// Get request schedule that not exist
+	requestSchedule, err = s.client.RequestSchedule("test", 9999)
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), requestSchedule)
+
+	// Check batch requests status
+	requestSchedule = &RequestSchedule{
+		RequestScheduleInfo: &RequestScheduleInfo{
+			Status: REQUEST_SCHEDULE_SCHEDULED,
+			Batch: &RequestScheduleBatch{
+				BatchRequests: []BatchRequest{
+					BatchRequest{OrderId: 1, Status: REQUEST_COMPLETED},
+					BatchRequest{OrderId: 2, Status: REQUEST_FAILED},
+					BatchRequest{OrderId: 3},
+				},
+			},
+		},
+	}
+	assert.False(s.T(), requestSchedule.IsFinished())
+	assert.Equal(s.T(), 2, requestSchedule.FinishedBatchRequests())
+	assert.Equal(s.T(), 1, len(requestSchedule.FailedBatchRequests()))
+}
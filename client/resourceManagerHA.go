@@ -0,0 +1,261 @@
+// This file permit to enable the ResourceManager HA, like the wizard of Ambari UI
+// Ambari documentation: https://docs.hortonworks.com/HDPDocuments/Ambari-2.6.2.2/bk_ambari-operations/content/how_to_configure_rm_high_availability.html
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const (
+	RESOURCEMANAGER_HA_STEP_STOP_SERVICES           = "STOP_SERVICES"
+	RESOURCEMANAGER_HA_STEP_INSTALL_RESOURCEMANAGER = "INSTALL_RESOURCEMANAGER"
+	RESOURCEMANAGER_HA_STEP_CONFIGURE               = "CONFIGURE"
+	RESOURCEMANAGER_HA_STEP_START_SERVICES          = "START_SERVICES"
+	RESOURCEMANAGER_HA_CLUSTER_ID                   = "yarn-cluster"
+	COMPONENT_RESOURCEMANAGER                       = "RESOURCEMANAGER"
+)
+
+// ResourceManagerHASteps is the ordered list of steps to enable ResourceManager HA
+var ResourceManagerHASteps = []string{
+	RESOURCEMANAGER_HA_STEP_STOP_SERVICES,
+	RESOURCEMANAGER_HA_STEP_INSTALL_RESOURCEMANAGER,
+	RESOURCEMANAGER_HA_STEP_CONFIGURE,
+	RESOURCEMANAGER_HA_STEP_START_SERVICES,
+}
+
+// ResourceManagerHAPlan describe the steps to enable ResourceManager HA
+// Steps are the steps not yet done and YarnSite is the yarn-site that will be set
+type ResourceManagerHAPlan struct {
+	ClusterName                   string         `json:"cluster_name"`
+	ResourceManagerHost           string         `json:"resourcemanager_host"`
+	AdditionalResourceManagerHost string         `json:"additional_resourcemanager_host"`
+	Steps                         []string       `json:"steps"`
+	YarnSite                      *Configuration `json:"yarn_site,omitempty"`
+}
+
+// ResourceManagerHACheckpoint keep the steps already done when enable ResourceManager HA
+type ResourceManagerHACheckpoint struct {
+	Checkpoint
+	ClusterName                   string `json:"cluster_name"`
+	ResourceManagerHost           string `json:"resourcemanager_host"`
+	AdditionalResourceManagerHost string `json:"additional_resourcemanager_host"`
+}
+
+// String permit to get plan object as Json string
+func (p *ResourceManagerHAPlan) String() string {
+	json, _ := json.Marshal(p)
+	return string(json)
+}
+
+// EnableResourceManagerHA permit to enable ResourceManager HA on cluster
+// It stop all services, install the additional ResourceManager on secondHost, update yarn-site and start all services.
+// Each step done is saved, so if the workflow is interrupted, you can call it again with the same host to resume it.
+// If dryRun is set to true, it only compute and return the plan, nothing is changed on cluster
+// It return the plan with the steps done
+// It return error if HA is already enabled, if secondHost is invalid or if something wrong when it call the API
+func (c *AmbariClient) EnableResourceManagerHA(clusterName string, secondHost string, dryRun bool) (*ResourceManagerHAPlan, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if secondHost == "" {
+		panic("SecondHost can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("SecondHost: ", secondHost)
+	log.Debug("DryRun: ", dryRun)
+
+	// Load the checkpoint to resume the workflow
+	checkpoint, err := c.ResourceManagerHACheckpoint(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	configurations, err := c.DesiredConfigurations(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil {
+		resourceManagerHost, err := c.checkResourceManagerHA(clusterName, secondHost, configurations)
+		if err != nil {
+			return nil, err
+		}
+		checkpoint = &ResourceManagerHACheckpoint{
+			ClusterName:                   clusterName,
+			ResourceManagerHost:           resourceManagerHost,
+			AdditionalResourceManagerHost: secondHost,
+			Checkpoint: Checkpoint{
+				Steps: make([]string, 0, len(ResourceManagerHASteps)),
+			},
+		}
+	} else if checkpoint.AdditionalResourceManagerHost != secondHost {
+		return nil, NewAmbariError(409, "Enable ResourceManager HA is already in progress with additional ResourceManager on %s", checkpoint.AdditionalResourceManagerHost)
+	} else {
+		log.Infof("Resume enable ResourceManager HA, steps already done: %s", strings.Join(checkpoint.Steps, ","))
+	}
+
+	// Compute the plan
+	plan := &ResourceManagerHAPlan{
+		ClusterName:                   clusterName,
+		ResourceManagerHost:           checkpoint.ResourceManagerHost,
+		AdditionalResourceManagerHost: secondHost,
+		Steps:                         make([]string, 0, len(ResourceManagerHASteps)),
+	}
+	for _, step := range ResourceManagerHASteps {
+		if checkpoint.IsDone(step) == false {
+			plan.Steps = append(plan.Steps, step)
+		}
+	}
+	if checkpoint.IsDone(RESOURCEMANAGER_HA_STEP_CONFIGURE) == false {
+		zookeeperQuorum, err := c.zookeeperQuorum(clusterName, configurations)
+		if err != nil {
+			return nil, err
+		}
+		yarnSite := configurations["yarn-site"]
+		plan.YarnSite = ResourceManagerHAConfiguration(&yarnSite, []string{checkpoint.ResourceManagerHost, secondHost}, zookeeperQuorum)
+	}
+	log.Debugf("Plan: %s", plan)
+	if dryRun == true {
+		for _, step := range plan.Steps {
+			log.Infof("Enable ResourceManager HA (dry run): %s", step)
+		}
+		return plan, nil
+	}
+
+	for _, step := range plan.Steps {
+		log.Infof("Enable ResourceManager HA: %s", step)
+
+		switch step {
+		case RESOURCEMANAGER_HA_STEP_STOP_SERVICES:
+			err = c.StopServicesInOrder(clusterName, nil, false, false)
+		case RESOURCEMANAGER_HA_STEP_INSTALL_RESOURCEMANAGER:
+			err = c.installHostComponent(clusterName, "YARN", COMPONENT_RESOURCEMANAGER, secondHost)
+		case RESOURCEMANAGER_HA_STEP_CONFIGURE:
+			plan.YarnSite.Tag = fmt.Sprintf("version_%s", time.Now().Format("2006-01-02_15:04:05"))
+			_, err = c.CreateConfigurationOnCluster(clusterName, plan.YarnSite)
+		case RESOURCEMANAGER_HA_STEP_START_SERVICES:
+			err = c.StartServicesInOrder(clusterName, nil, false)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		checkpoint.Done(step)
+		err = c.saveCheckpoint(resourceManagerHACheckpointKey(clusterName), checkpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The workflow is finished, remove the checkpoint
+	err = c.deleteCheckpoint(resourceManagerHACheckpointKey(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return plan: %s", plan)
+
+	return plan, nil
+}
+
+// ResourceManagerHACheckpoint permit to get the checkpoint of ResourceManager HA enabling in progress
+// It return nil if there are no workflow in progress
+// It return error if something wrong when it call the API
+func (c *AmbariClient) ResourceManagerHACheckpoint(clusterName string) (*ResourceManagerHACheckpoint, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+
+	checkpoint := &ResourceManagerHACheckpoint{}
+	isFound, err := c.loadCheckpoint(resourceManagerHACheckpointKey(clusterName), checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if isFound == false {
+		return nil, nil
+	}
+
+	return checkpoint, nil
+}
+
+func resourceManagerHACheckpointKey(clusterName string) string {
+	return fmt.Sprintf("go-ambari-rest-resourcemanager-ha-%s", clusterName)
+}
+
+// checkResourceManagerHA permit to check that ResourceManager HA can be enabled with the second host
+// It return the host of current ResourceManager
+func (c *AmbariClient) checkResourceManagerHA(clusterName string, secondHost string, configurations map[string]Configuration) (string, error) {
+
+	if yarnSite, isFound := configurations["yarn-site"]; isFound && yarnSite.Properties["yarn.resourcemanager.ha.enabled"] == "true" {
+		return "", NewAmbariError(409, "ResourceManager HA is already enabled")
+	}
+	resourceManagerHosts, err := c.componentHosts(clusterName, COMPONENT_RESOURCEMANAGER)
+	if err != nil {
+		return "", err
+	}
+	if len(resourceManagerHosts) != 1 {
+		return "", NewAmbariError(400, "You need one ResourceManager to enable HA, there are %d", len(resourceManagerHosts))
+	}
+	if resourceManagerHosts[0] == secondHost {
+		return "", NewAmbariError(400, "The additional ResourceManager must be on other host than %s", secondHost)
+	}
+	host, err := c.Host(secondHost)
+	if err != nil {
+		return "", err
+	}
+	if host == nil {
+		return "", NewAmbariError(404, "Host %s not found", secondHost)
+	}
+	zookeeperHosts, err := c.componentHosts(clusterName, COMPONENT_ZOOKEEPER_SERVER)
+	if err != nil {
+		return "", err
+	}
+	if len(zookeeperHosts) == 0 {
+		return "", NewAmbariError(400, "You need ZooKeeper to enable ResourceManager HA")
+	}
+
+	return resourceManagerHosts[0], nil
+}
+
+// ResourceManagerHAConfiguration permit to compute yarn-site with ResourceManager HA
+// The ports are kept from the current ResourceManager web addresses
+func ResourceManagerHAConfiguration(yarnSite *Configuration, resourceManagerHosts []string, zookeeperQuorum string) *Configuration {
+
+	newYarnSite := yarnSite.Merge(&Configuration{})
+
+	// ResourceManager addresses
+	addresses := map[string]string{
+		"yarn.resourcemanager.webapp.address":       "8088",
+		"yarn.resourcemanager.webapp.https.address": "8090",
+	}
+	resourceManagerIds := make([]string, 0, len(resourceManagerHosts))
+	for i, hostname := range resourceManagerHosts {
+		resourceManagerIds = append(resourceManagerIds, fmt.Sprintf("rm%d", i+1))
+		newYarnSite.Properties[fmt.Sprintf("yarn.resourcemanager.hostname.%s", resourceManagerIds[i])] = hostname
+	}
+	for property, port := range addresses {
+		if value := yarnSite.Properties[property]; strings.Contains(value, ":") {
+			port = value[strings.LastIndex(value, ":")+1:]
+		}
+		for i, hostname := range resourceManagerHosts {
+			newYarnSite.Properties[fmt.Sprintf("%s.%s", property, resourceManagerIds[i])] = fmt.Sprintf("%s:%s", hostname, port)
+		}
+	}
+
+	if newYarnSite.Properties["yarn.resourcemanager.cluster-id"] == "" {
+		newYarnSite.Properties["yarn.resourcemanager.cluster-id"] = RESOURCEMANAGER_HA_CLUSTER_ID
+	}
+	newYarnSite.Properties["yarn.resourcemanager.ha.enabled"] = "true"
+	newYarnSite.Properties["yarn.resourcemanager.ha.rm-ids"] = strings.Join(resourceManagerIds, ",")
+	newYarnSite.Properties["yarn.resourcemanager.ha.automatic-failover.zk-base-path"] = "/yarn-leader-election"
+	newYarnSite.Properties["yarn.resourcemanager.recovery.enabled"] = "true"
+	newYarnSite.Properties["yarn.resourcemanager.store.class"] = "org.apache.hadoop.yarn.server.resourcemanager.recovery.ZKRMStateStore"
+	newYarnSite.Properties["yarn.resourcemanager.zk-address"] = zookeeperQuorum
+
+	return newYarnSite
+}
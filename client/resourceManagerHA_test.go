@@ -0,0 +1,45 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestEnableResourceManagerHA() {
+
+	// Second host not exist
+	plan, err := s.client.EnableResourceManagerHA("test", "fake", true)
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), plan)
+
+	// There are no workflow in progress
+	checkpoint, err := s.client.ResourceManagerHACheckpoint("test")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), checkpoint)
+
+	// Check the steps done
+	checkpoint = &ResourceManagerHACheckpoint{
+		Checkpoint: Checkpoint{
+			Steps: []string{RESOURCEMANAGER_HA_STEP_STOP_SERVICES},
+		},
+	}
+	assert.True(s.T(), checkpoint.IsDone(RESOURCEMANAGER_HA_STEP_STOP_SERVICES))
+	assert.False(s.T(), checkpoint.IsDone(RESOURCEMANAGER_HA_STEP_CONFIGURE))
+
+	// Check the configuration
+	yarnSite := &Configuration{
+		Type: "yarn-site",
+		Properties: map[string]string{
+			"yarn.resourcemanager.hostname":       "master01",
+			"yarn.resourcemanager.webapp.address": "master01:8188",
+		},
+	}
+	newYarnSite := ResourceManagerHAConfiguration(yarnSite, []string{"master01", "master02"}, "master01:2181,master02:2181")
+	assert.Equal(s.T(), "true", newYarnSite.Properties["yarn.resourcemanager.ha.enabled"])
+	assert.Equal(s.T(), "rm1,rm2", newYarnSite.Properties["yarn.resourcemanager.ha.rm-ids"])
+	assert.Equal(s.T(), "master02", newYarnSite.Properties["yarn.resourcemanager.hostname.rm2"])
+	assert.Equal(s.T(), "master02:8188", newYarnSite.Properties["yarn.resourcemanager.webapp.address.rm2"])
+	assert.Equal(s.T(), "master01:8090", newYarnSite.Properties["yarn.resourcemanager.webapp.https.address.rm1"])
+	assert.Equal(s.T(), RESOURCEMANAGER_HA_CLUSTER_ID, newYarnSite.Properties["yarn.resourcemanager.cluster-id"])
+	assert.Equal(s.T(), "master01:2181,master02:2181", newYarnSite.Properties["yarn.resourcemanager.zk-address"])
+	assert.Equal(s.T(), "master01", newYarnSite.Properties["yarn.resourcemanager.hostname"])
+}
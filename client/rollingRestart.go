@@ -0,0 +1,179 @@
+// This file permit to restart component on many hosts in batches, like the rolling restart of Ambari UI
+// It avoid data unavailability when restart DataNodes or NodeManagers on large cluster
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_ROLLING_RESTART_BATCH_SIZE     = 1
+	DEFAULT_ROLLING_RESTART_BATCH_INTERVAL = 2 * time.Minute
+)
+
+// RollingRestartOptions permit to tune the rolling restart
+// BatchSize is the number of hosts restarted in same time
+// BatchInterval is the pause between two batches
+// ToleratedFailures is the number of failed tasks tolerated in each batch before abort the rolling restart
+// If OnlyStaleConfigs is set to true, it restart only the components that need to be restarted to apply configuration
+// Timeout is the maximum time to wait the end of rolling restart, 0 to wait without limit
+type RollingRestartOptions struct {
+	BatchSize         int
+	BatchInterval     time.Duration
+	ToleratedFailures int
+	OnlyStaleConfigs  bool
+	Timeout           time.Duration
+}
+
+// RollingRestart permit to restart component on all hosts in batches with a request schedule, and wait the end
+// The hosts where the component is in maintenance state are skipped
+// If opts is nil, it restart one host at a time with 2 minutes between hosts and without tolerated failure
+// It return the request schedule to get the result of each batch, with the error if the rolling restart is aborted or not finished before the timeout
+// It return nil if there are no host to restart
+// It return error if component not found, if the rolling restart is aborted, if it's not finished before the timeout or if something wrong when it call the API
+func (c *AmbariClient) RollingRestart(clusterName string, componentName string, opts *RollingRestartOptions) (*RequestSchedule, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if componentName == "" {
+		panic("ComponentName can't be empty")
+	}
+	if opts == nil {
+		opts = &RollingRestartOptions{
+			BatchSize:     DEFAULT_ROLLING_RESTART_BATCH_SIZE,
+			BatchInterval: DEFAULT_ROLLING_RESTART_BATCH_INTERVAL,
+		}
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = DEFAULT_ROLLING_RESTART_BATCH_SIZE
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ComponentName: ", componentName)
+	log.Debugf("Options: %+v", opts)
+
+	// Get the hosts of component
+	path := fmt.Sprintf("/clusters/%s/host_components", clusterName)
+	resp, err := c.Client().R().SetQueryParam("HostRoles/component_name", componentName).SetQueryParam("fields", "HostRoles/service_name,HostRoles/component_name,HostRoles/host_name,HostRoles/maintenance_state,HostRoles/stale_configs").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	hostComponents := &HostComponents{}
+	err = json.Unmarshal(resp.Body(), hostComponents)
+	if err != nil {
+		return nil, err
+	}
+	if len(hostComponents.Items) == 0 {
+		return nil, NewAmbariError(404, "Component %s not found in cluster %s", componentName, clusterName)
+	}
+	serviceName := hostComponents.Items[0].HostComponentInfo.ServiceName
+	hostnames := make([]string, 0, len(hostComponents.Items))
+	for _, hostComponent := range hostComponents.Items {
+		if hostComponent.HostComponentInfo.MaintenanceState != "" && hostComponent.HostComponentInfo.MaintenanceState != MAINTENANCE_STATE_OFF {
+			log.Infof("Skip %s on %s because of it's in maintenance state", componentName, hostComponent.HostComponentInfo.Hostname)
+			continue
+		}
+		if opts.OnlyStaleConfigs == true && hostComponent.HostComponentInfo.StaleConfigs == false {
+			continue
+		}
+		hostnames = append(hostnames, hostComponent.HostComponentInfo.Hostname)
+	}
+	if len(hostnames) == 0 {
+		log.Debugf("There are no host to restart")
+		return nil, nil
+	}
+	sort.Strings(hostnames)
+
+	// Create one batch request per group of hosts
+	nbBatches := (len(hostnames) + opts.BatchSize - 1) / opts.BatchSize
+	batchRequests := make([]BatchRequestSpec, 0, nbBatches)
+	for i := 0; i < nbBatches; i++ {
+		end := (i + 1) * opts.BatchSize
+		if end > len(hostnames) {
+			end = len(hostnames)
+		}
+		batchRequests = append(batchRequests, BatchRequestSpec{
+			OrderId: i + 1,
+			Type:    BATCH_REQUEST_POST,
+			Uri:     c.requestsUri(clusterName),
+			Body: &Request{
+				RequestInfo: &RequestInfo{
+					Context: fmt.Sprintf("Rolling restart of %s - batch %d of %d from API", componentName, i+1, nbBatches),
+					Command: COMMAND_RESTART,
+					OperationLevel: &RequestOperationLevel{
+						Level:       OPERATION_LEVEL_SERVICE,
+						ClusterName: clusterName,
+						ServiceName: serviceName,
+					},
+				},
+				ResourceFilters: []RequestResourceFilter{
+					RequestResourceFilter{
+						ServiceName:   serviceName,
+						ComponentName: componentName,
+						Hosts:         strings.Join(hostnames[i*opts.BatchSize:end], ","),
+					},
+				},
+			},
+		})
+	}
+
+	// Create the request schedule and wait the end
+	requestSchedule, err := c.CreateRequestSchedule(clusterName, &RequestScheduleSpec{
+		RequestScheduleSpecInfo: &RequestScheduleSpecInfo{
+			Description: fmt.Sprintf("Rolling restart of %s from API", componentName),
+			Batch: []BatchSpec{
+				BatchSpec{
+					Requests: batchRequests,
+				},
+				BatchSpec{
+					BatchSettings: &BatchSettingsSpec{
+						BatchSeparationInSeconds:     int(opts.BatchInterval / time.Second),
+						TaskFailureTolerance:         opts.ToleratedFailures * nbBatches,
+						TaskFailureTolerancePerBatch: opts.ToleratedFailures,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if requestSchedule == nil {
+		return nil, NewAmbariError(500, "Can't get the rolling restart of %s that just created", componentName)
+	}
+	log.Infof("Rolling restart of %s on %d hosts in %d batches (request schedule %d)", componentName, len(hostnames), nbBatches, requestSchedule.RequestScheduleInfo.Id)
+	// The request schedule is returned on timeout, so it can be followed or deleted
+	err = requestSchedule.WaitWithTimeout(c, clusterName, opts.Timeout)
+	if err != nil {
+		if ambariError, isOk := err.(AmbariError); isOk && ambariError.Code == 408 {
+			return requestSchedule, err
+		}
+		return nil, err
+	}
+
+	// Check the result
+	// The failures are tolerated only if all batches are run
+	failedBatchRequests := requestSchedule.FailedBatchRequests()
+	if requestSchedule.RequestScheduleInfo.Status == REQUEST_SCHEDULE_ABORTED || requestSchedule.FinishedBatchRequests() < nbBatches {
+		return requestSchedule, NewAmbariError(500, "Rolling restart of %s is aborted after %d/%d batches, %d batches failed", componentName, requestSchedule.FinishedBatchRequests(), nbBatches, len(failedBatchRequests))
+	}
+	if len(failedBatchRequests) > 0 && opts.ToleratedFailures == 0 {
+		return requestSchedule, NewAmbariError(500, "Rolling restart of %s failed on %d batches", componentName, len(failedBatchRequests))
+	}
+	for _, batchRequest := range failedBatchRequests {
+		log.Warnf("Batch %d of rolling restart of %s is %s", batchRequest.OrderId, componentName, batchRequest.Status)
+	}
+	log.Debugf("Return request schedule: %s", requestSchedule)
+
+	return requestSchedule, nil
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"time"
+)
+
+func (s *ClientTestSuite) TestRollingRestart() {
+
+	// Rolling restart of component
+	requestSchedule, err := s.client.RollingRestart("test", "ZOOKEEPER_SERVER", &RollingRestartOptions{BatchSize: 1, Timeout: 10 * time.Minute})
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), requestSchedule)
+	if requestSchedule != nil {
+		assert.Equal(s.T(), requestSchedule.FinishedBatchRequests(), len(requestSchedule.RequestScheduleInfo.Batch.BatchRequests))
+		assert.Empty(s.T(), requestSchedule.FailedBatchRequests())
+	}
+
+	// Rolling restart only components with stale configs
+	_, err = s.client.RollingRestart("test", "ZOOKEEPER_SERVER", &RollingRestartOptions{BatchSize: 2, OnlyStaleConfigs: true})
+	assert.NoError(s.T(), err)
+
+	// Rolling restart of component that not exist
+	_, err = s.client.RollingRestart("test", "FAKE", nil)
+	assert.Error(s.T(), err)
+}
@@ -7,24 +7,39 @@ import (
 	"encoding/json"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
 	"time"
 )
 
 const (
-	SERVICE_STARTED       = "STARTED"
-	SERVICE_STOPPED       = "INSTALLED"
-	SERVICE_INSTALLED     = "INSTALLED"
-	SERVICE_UNKNOWN       = "UNKNOWN"
-	SERVICE_INIT          = "INIT"
-	MAINTENANCE_STATE_ON  = "ON"
-	MAINTENANCE_STATE_OFF = "OFF"
+	SERVICE_STARTED         = "STARTED"
+	SERVICE_STOPPED         = "INSTALLED"
+	SERVICE_INSTALLED       = "INSTALLED"
+	SERVICE_UNKNOWN         = "UNKNOWN"
+	SERVICE_INIT            = "INIT"
+	SERVICE_INSTALL_FAILED  = "INSTALL_FAILED"
+	DEFAULT_INSTALL_TIMEOUT = 1 * time.Hour
+	MAINTENANCE_STATE_ON    = "ON"
+	MAINTENANCE_STATE_OFF   = "OFF"
 )
 
+// ServiceCheck is the result of service check
+type ServiceCheck struct {
+	ServiceName string `json:"service_name"`
+	RequestId   int    `json:"request_id"`
+	Status      string `json:"status"`
+	Tasks       []Task `json:"tasks,omitempty"`
+}
+
 // Service object
 type Service struct {
 	ServiceInfo *ServiceInfo `json:"ServiceInfo"`
 	Components  []Component  `json:"components,omitempty"`
 }
+type Services struct {
+	Items []Service `json:"items,omitempty"`
+}
 type ServiceInfo struct {
 	ClusterName      string `json:"cluster_name,omitempty"`
 	ServiceName      string `json:"service_name,omitempty"`
@@ -39,6 +54,25 @@ func (s *Service) String() string {
 	return string(json)
 }
 
+// String permit to get service check object as Json string
+func (s *ServiceCheck) String() string {
+	json, _ := json.Marshal(s)
+	return string(json)
+}
+
+// IsPassed permit to know if the service check is successfull
+func (s *ServiceCheck) IsPassed() bool {
+	return s.Status == REQUEST_COMPLETED
+}
+
+// ServiceCheckCommand permit to get the command name used to check the service
+func ServiceCheckCommand(serviceName string) string {
+	if serviceName == "ZOOKEEPER" {
+		return "ZOOKEEPER_QUORUM_SERVICE_CHECK"
+	}
+	return fmt.Sprintf("%s_SERVICE_CHECK", serviceName)
+}
+
 // ClearBeforeSave permit to clean service before save or update it
 func (s *Service) CleanBeforeSave() {
 	s.Components = nil
@@ -121,6 +155,46 @@ func (c *AmbariClient) Service(clusterName string, serviceName string) (*Service
 	return service, nil
 }
 
+// Services permit to get all services in cluster with their components
+// If state is not empty, it return only the services with this state (STARTED, INSTALLED, etc.)
+// If maintenanceState is not empty, it return only the services with this maintenance state (ON or OFF)
+// It return the list of services
+// It return error if something wrong with the API call
+func (c *AmbariClient) Services(clusterName string, state string, maintenanceState string) ([]Service, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("State: ", state)
+	log.Debug("MaintenanceState: ", maintenanceState)
+
+	path := fmt.Sprintf("/clusters/%s/services", clusterName)
+	request := c.Client().R().SetQueryParam("fields", "ServiceInfo/*,components/ServiceComponentInfo/*")
+	if state != "" {
+		request.SetQueryParam("ServiceInfo/state", state)
+	}
+	if maintenanceState != "" {
+		request.SetQueryParam("ServiceInfo/maintenance_state", maintenanceState)
+	}
+	resp, err := request.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	services := &Services{}
+	err = json.Unmarshal(resp.Body(), services)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return %d services", len(services.Items))
+
+	return services.Items, nil
+}
+
 // UpdateService permit to update an existing sevice like service state
 // It return updated Service if all work fine
 // It return error if something wrong when it call the API
@@ -163,8 +237,18 @@ func (c *AmbariClient) UpdateService(service *Service) (*Service, error) {
 
 // DeleteService permit to delete an existing service
 // Before to delete service, it need to stop service and then delete all components
+// It delete the service even if other services depend on it, like DeleteServiceWithCheck with force set to true
 // It return error if service is not found
 func (c *AmbariClient) DeleteService(clusterName string, serviceName string) error {
+	return c.DeleteServiceWithCheck(clusterName, serviceName, true)
+}
+
+// DeleteServiceWithCheck permit to delete an existing service after checking that no other installed services depend on it
+// Before to delete service, it need to stop service and then delete all components
+// It refuse to delete the service if other installed services depend on it, except if force is set to true
+// Ambari remove the configuration types of service when it delete it (there are no API to remove them), so it check that the types not shared with other services are removed
+// It return error if service is not found, if some services depend on it, if some configuration types are kept or if something wrong when it call the API
+func (c *AmbariClient) DeleteServiceWithCheck(clusterName string, serviceName string, force bool) error {
 
 	if clusterName == "" {
 		panic("ClusterName can't be empty")
@@ -174,15 +258,53 @@ func (c *AmbariClient) DeleteService(clusterName string, serviceName string) err
 	}
 	log.Debug("ClusterName: ", clusterName)
 	log.Debug("ServiceName: ", serviceName)
+	log.Debug("Force: ", force)
+
+	// Get service
+	service, err := c.Service(clusterName, serviceName)
+	if err != nil {
+		return err
+	}
+	if service == nil {
+		return NewAmbariError(404, "Service %s not found in cluster %s", serviceName, clusterName)
+	}
 
-	// Stop service before to delete it
-	_, err := c.StopService(clusterName, serviceName, false, true)
+	// Check if other services depend on it
+	dependencies, err := c.ServiceDependencies(clusterName)
 	if err != nil {
 		return err
 	}
+	dependents := make([]string, 0)
+	for otherService, requiredServices := range dependencies {
+		for _, requiredService := range requiredServices {
+			if requiredService == serviceName {
+				dependents = append(dependents, otherService)
+			}
+		}
+	}
+	if len(dependents) > 0 {
+		sort.Strings(dependents)
+		if force == false {
+			return NewAmbariError(409, "Service %s can't be deleted because of services %s depend on it", serviceName, strings.Join(dependents, ","))
+		}
+		log.Warnf("Service %s is deleted even if services %s depend on it", serviceName, strings.Join(dependents, ","))
+	}
 
-	// Get service and remove all components
-	service, err := c.Service(clusterName, serviceName)
+	// Get the configuration types of service
+	configurationTypes, err := c.serviceConfigurationTypes(clusterName, serviceName, dependencies)
+	if err != nil {
+		return err
+	}
+
+	// Stop service before to delete it. The service that is not installed can't be stopped, else it will be installed.
+	if service.ServiceInfo.State != SERVICE_INIT && service.ServiceInfo.State != SERVICE_INSTALL_FAILED {
+		_, err = c.StopService(clusterName, serviceName, false, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remove all components
 	for _, component := range service.Components {
 		err := c.DeleteComponent(clusterName, serviceName, component.ComponentInfo.ComponentName)
 		if err != nil {
@@ -201,10 +323,68 @@ func (c *AmbariClient) DeleteService(clusterName string, serviceName string) err
 		return NewAmbariError(resp.StatusCode(), resp.Status())
 	}
 
+	// Check the configuration types are removed
+	if len(configurationTypes) > 0 {
+		configurations, err := c.DesiredConfigurations(clusterName)
+		if err != nil {
+			return err
+		}
+		keptConfigurationTypes := make([]string, 0)
+		for _, configurationType := range configurationTypes {
+			if _, isFound := configurations[configurationType]; isFound {
+				keptConfigurationTypes = append(keptConfigurationTypes, configurationType)
+			}
+		}
+		if len(keptConfigurationTypes) > 0 {
+			return NewAmbariError(500, "Service %s is deleted but configurations %s are kept on cluster %s", serviceName, strings.Join(keptConfigurationTypes, ","), clusterName)
+		}
+		log.Debugf("Configurations %s of service %s are removed", strings.Join(configurationTypes, ","), serviceName)
+	}
+
 	return nil
 
 }
 
+// serviceConfigurationTypes permit to get the configuration types of service from stack, without the types shared with other installed services
+func (c *AmbariClient) serviceConfigurationTypes(clusterName string, serviceName string, installedServices map[string][]string) ([]string, error) {
+
+	cluster, err := c.Cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, NewAmbariError(404, "Cluster %s not found", clusterName)
+	}
+	stackName, stackVersion := cluster.StackNameAndVersion()
+	stackServices, err := c.StackServices(stackName, stackVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedConfigurationTypes := make(map[string]bool)
+	var serviceConfigurationTypes map[string]interface{}
+	for _, stackService := range stackServices {
+		if stackService.StackServiceInfo.ServiceName == serviceName {
+			serviceConfigurationTypes = stackService.StackServiceInfo.ConfigTypes
+		} else if _, isInstalled := installedServices[stackService.StackServiceInfo.ServiceName]; isInstalled {
+			for configurationType := range stackService.StackServiceInfo.ConfigTypes {
+				sharedConfigurationTypes[configurationType] = true
+			}
+		}
+	}
+
+	configurationTypes := make([]string, 0, len(serviceConfigurationTypes))
+	for configurationType := range serviceConfigurationTypes {
+		if sharedConfigurationTypes[configurationType] == false {
+			configurationTypes = append(configurationTypes, configurationType)
+		}
+	}
+	sort.Strings(configurationTypes)
+	log.Debugf("Configuration types of service %s: %v", serviceName, configurationTypes)
+
+	return configurationTypes, nil
+}
+
 // SendRequestService permit to start / stop service on ambari with message displayed on operation task in Ambari UI
 // It keep only State and MaintenanceState field when it send the request
 // It return RequestTask if all work fine
@@ -256,32 +436,69 @@ func (c *AmbariClient) SendRequestService(request *Request) (*RequestTask, error
 
 // InstallService permit to start the service installation
 // It must have the service setting and component associated to host to work
+// It wait the end of installation no more than DEFAULT_INSTALL_TIMEOUT
 // It return service if all work fine
-// It return error if something wrong when it call the API
+// It return error with the stderr of failed tasks if the installation failed, or if something wrong when it call the API
 func (c *AmbariClient) InstallService(service *Service) (*Service, error) {
+	return c.InstallServiceWithTimeout(service, DEFAULT_INSTALL_TIMEOUT)
+}
+
+// InstallServiceWithTimeout permit to start the service installation and wait the end of installation no more than timeout
+// If timeout is 0, it wait without limit
+// It return service if all work fine
+// It return error with the stderr of failed tasks if the installation failed, if the timeout is reached or if something wrong when it call the API
+func (c *AmbariClient) InstallServiceWithTimeout(service *Service, timeout time.Duration) (*Service, error) {
 	if service == nil {
 		panic("Service can't be nil")
 	}
 	log.Debug("Service: ", service)
+	log.Debug("Timeout: ", timeout)
 
 	// Check if service is already installed
-	if service.ServiceInfo.State == SERVICE_INSTALLED {
+	if service.ServiceInfo.State == SERVICE_INSTALLED || service.ServiceInfo.State == SERVICE_STARTED {
 		log.Debugf("The service %s is already installed", service.ServiceInfo.ServiceName)
 		return service, nil
 	}
+	clusterName := service.ServiceInfo.ClusterName
+	serviceName := service.ServiceInfo.ServiceName
 
 	// Install service and wait
 	service.ServiceInfo.State = SERVICE_INSTALLED
-	service, err := c.UpdateService(service)
+	request := &Request{
+		RequestInfo: &RequestInfo{
+			Context: fmt.Sprintf("Install service %s from API", serviceName),
+		},
+		Body: service,
+	}
+	requestTask, err := c.SendRequestService(request)
 	if err != nil {
 		return nil, err
 	}
-	for service.ServiceInfo.State != SERVICE_INSTALLED {
-		time.Sleep(10 * time.Second)
-		service, err = c.Service(service.ServiceInfo.ClusterName, service.ServiceInfo.ServiceName)
+	if requestTask != nil {
+
+		// Wait the end of the request
+		err = requestTask.WaitWithTimeout(c, clusterName, timeout)
 		if err != nil {
 			return nil, err
 		}
+
+		// Check the status
+		if requestTask.RequestTaskInfo.Status != REQUEST_COMPLETED {
+			return nil, requestTask.Error(c, clusterName)
+		}
+	}
+
+	// Check the service state
+	service, err = c.Service(clusterName, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if service == nil {
+		return nil, NewAmbariError(404, "Service %s not found in cluster %s", serviceName, clusterName)
+	}
+	// Ambari not create request when there are nothing to install, so the state must be checked even without request
+	if service.ServiceInfo.State != SERVICE_INSTALLED && service.ServiceInfo.State != SERVICE_STARTED {
+		return nil, NewAmbariError(500, "Service %s is not installed, it's in state %s", serviceName, service.ServiceInfo.State)
 	}
 
 	return service, nil
@@ -436,6 +653,202 @@ func (c *AmbariClient) StopService(clusterName string, serviceName string, enabl
 	return service, nil
 }
 
+// RestartService permit to restart all components of service (except the clients) with RESTART command
+// The components on host in maintenance state are not restarted
+// It return error with the failed hosts if the restart failed or if something wrong when it call the API
+func (c *AmbariClient) RestartService(clusterName string, serviceName string) error {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ServiceName: ", serviceName)
+
+	return c.restartComponents(clusterName, serviceName, "", nil, fmt.Sprintf("Restart service %s from API", serviceName))
+}
+
+// restartComponents permit to send RESTART command on components of service and wait the end of the request
+// If componentName is empty, it restart all components of service. If hostnames is empty, it restart on all hosts.
+// The clients and the host components in maintenance state are skipped
+// It return error if componentName is a client or if there are no component to restart on one of hostnames
+func (c *AmbariClient) restartComponents(clusterName string, serviceName string, componentName string, hostnames []string, context string) error {
+
+	// Get the components with their hosts
+	path := fmt.Sprintf("/clusters/%s/services/%s/components", clusterName, serviceName)
+	request := c.Client().R().SetQueryParam("fields", "ServiceComponentInfo/component_name,ServiceComponentInfo/category,host_components/HostRoles/host_name,host_components/HostRoles/maintenance_state")
+	if componentName != "" {
+		request.SetQueryParam("ServiceComponentInfo/component_name", componentName)
+	}
+	resp, err := request.Get(path)
+	if err != nil {
+		return err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	components := &Components{}
+	err = json.Unmarshal(resp.Body(), components)
+	if err != nil {
+		return err
+	}
+	if componentName != "" && len(components.Items) == 0 {
+		return NewAmbariError(404, "Component %s not found in service %s", componentName, serviceName)
+	}
+
+	// Compute the hosts where to restart each component
+	selectedHosts := make(map[string]bool, len(hostnames))
+	for _, hostname := range hostnames {
+		selectedHosts[hostname] = true
+	}
+	foundHosts := make(map[string]bool, len(hostnames))
+	nbSkipped := 0
+	resourceFilters := make([]RequestResourceFilter, 0, len(components.Items))
+	for _, component := range components.Items {
+		if component.ComponentInfo.Category == COMPONENT_CLIENT {
+			if componentName != "" {
+				return NewAmbariError(400, "Component %s is a client, it can't be restarted", componentName)
+			}
+			continue
+		}
+		hosts := make([]string, 0, len(component.HostComponents))
+		for _, hostComponent := range component.HostComponents {
+			if len(selectedHosts) > 0 && selectedHosts[hostComponent.HostComponentInfo.Hostname] == false {
+				continue
+			}
+			foundHosts[hostComponent.HostComponentInfo.Hostname] = true
+			if hostComponent.HostComponentInfo.MaintenanceState != "" && hostComponent.HostComponentInfo.MaintenanceState != MAINTENANCE_STATE_OFF {
+				log.Infof("Skip %s on %s because of it's in maintenance state", component.ComponentInfo.ComponentName, hostComponent.HostComponentInfo.Hostname)
+				nbSkipped++
+				continue
+			}
+			hosts = append(hosts, hostComponent.HostComponentInfo.Hostname)
+		}
+		if len(hosts) > 0 {
+			resourceFilters = append(resourceFilters, RequestResourceFilter{
+				ServiceName:   serviceName,
+				ComponentName: component.ComponentInfo.ComponentName,
+				Hosts:         strings.Join(hosts, ","),
+			})
+		}
+	}
+	for _, hostname := range hostnames {
+		if foundHosts[hostname] == false {
+			if componentName != "" {
+				return NewAmbariError(404, "Component %s not found on host %s", componentName, hostname)
+			}
+			return NewAmbariError(404, "Service %s has no component to restart on host %s", serviceName, hostname)
+		}
+	}
+	if len(resourceFilters) == 0 {
+		if nbSkipped > 0 {
+			log.Infof("There are no component to restart, the %d host components are in maintenance state", nbSkipped)
+		} else {
+			log.Debugf("There are no component to restart")
+		}
+		return nil
+	}
+
+	// Send the restart command and wait
+	requestTask, err := c.SendRequest(clusterName, &Request{
+		RequestInfo: &RequestInfo{
+			Context: context,
+			Command: COMMAND_RESTART,
+			OperationLevel: &RequestOperationLevel{
+				Level:       OPERATION_LEVEL_SERVICE,
+				ClusterName: clusterName,
+				ServiceName: serviceName,
+			},
+		},
+		ResourceFilters: resourceFilters,
+	})
+	if err != nil {
+		return err
+	}
+	if requestTask != nil {
+
+		// Wait the end of the request
+		err = requestTask.Wait(c, clusterName)
+		if err != nil {
+			return err
+		}
+
+		// Check the status
+		if requestTask.RequestTaskInfo.Status != REQUEST_COMPLETED {
+			return requestTask.Error(c, clusterName)
+		}
+	}
+
+	return nil
+}
+
+// RunServiceCheck permit to run the service check and wait the result
+// It return the service check with the status and the output of each task. The service check failed if the status is not COMPLETED.
+// It return error if service not found or if something wrong when it call the API
+func (c *AmbariClient) RunServiceCheck(clusterName string, serviceName string) (*ServiceCheck, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ServiceName: ", serviceName)
+
+	// Check the service exist
+	service, err := c.Service(clusterName, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if service == nil {
+		return nil, NewAmbariError(404, "Service %s not found in cluster %s", serviceName, clusterName)
+	}
+
+	// Run the service check
+	requestTask, err := c.SendRequest(clusterName, &Request{
+		RequestInfo: &RequestInfo{
+			Context: fmt.Sprintf("%s Service Check from API", serviceName),
+			Command: ServiceCheckCommand(serviceName),
+		},
+		ResourceFilters: []RequestResourceFilter{
+			RequestResourceFilter{
+				ServiceName: serviceName,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if requestTask == nil {
+		return nil, NewAmbariError(500, "Service check of %s is not created", serviceName)
+	}
+
+	// Wait the end of the request
+	err = requestTask.Wait(c, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the result
+	tasks, err := c.Tasks(clusterName, requestTask.RequestTaskInfo.Id)
+	if err != nil {
+		return nil, err
+	}
+	serviceCheck := &ServiceCheck{
+		ServiceName: serviceName,
+		RequestId:   requestTask.RequestTaskInfo.Id,
+		Status:      requestTask.RequestTaskInfo.Status,
+		Tasks:       tasks,
+	}
+	log.Debugf("Return service check: %s", serviceCheck)
+
+	return serviceCheck, nil
+}
+
 // StopAllServices stop all services in HDP cluster.
 // If enableMaintenanceMode is set to true, it will put all services in maintenance state after stopped all services.
 // If force is set to true, it will remove maintenance state in all services before stop all services. In this way, it will stop all services.
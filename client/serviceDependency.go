@@ -0,0 +1,212 @@
+// This file permit to start and stop services in the order of their dependencies
+// The dependencies are read from the stack metadata (required services)
+
+package client
+
+import (
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+)
+
+// ServiceDependencies permit to get the required services of each service installed on cluster
+// Only the required services that are installed on cluster are returned
+// It return error if cluster not found or if something wrong when it call the API
+func (c *AmbariClient) ServiceDependencies(clusterName string) (map[string][]string, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+
+	cluster, err := c.Cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, NewAmbariError(404, "Cluster %s not found", clusterName)
+	}
+	stackName, stackVersion := cluster.StackNameAndVersion()
+
+	stackServices, err := c.StackServices(stackName, stackVersion)
+	if err != nil {
+		return nil, err
+	}
+	requiredServices := make(map[string][]string, len(stackServices))
+	for _, stackService := range stackServices {
+		requiredServices[stackService.StackServiceInfo.ServiceName] = stackService.StackServiceInfo.RequiredServices
+	}
+
+	installedServices := make(map[string]bool, len(cluster.Services))
+	for _, service := range cluster.Services {
+		installedServices[service.ServiceInfo.ServiceName] = true
+	}
+	dependencies := make(map[string][]string, len(installedServices))
+	for serviceName := range installedServices {
+		dependencies[serviceName] = make([]string, 0)
+		for _, requiredService := range requiredServices[serviceName] {
+			if installedServices[requiredService] == true && requiredService != serviceName {
+				dependencies[serviceName] = append(dependencies[serviceName], requiredService)
+			}
+		}
+		sort.Strings(dependencies[serviceName])
+	}
+	log.Debugf("Return service dependencies: %v", dependencies)
+
+	return dependencies, nil
+}
+
+// ServicesStartOrder permit to get the order to start the services, the required services are started first
+// If serviceNames is empty, it return the order of all services installed on cluster
+// The dependencies between selected services through not selected services are kept
+// It return error if service not found, if there are cycle in dependencies or if something wrong when it call the API
+func (c *AmbariClient) ServicesStartOrder(clusterName string, serviceNames []string) ([]string, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("ServiceNames: ", serviceNames)
+
+	dependencies, err := c.ServiceDependencies(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	for _, serviceName := range serviceNames {
+		if _, isFound := dependencies[serviceName]; isFound == false {
+			return nil, NewAmbariError(404, "Service %s not found in cluster %s", serviceName, clusterName)
+		}
+	}
+
+	orderedServices, err := sortServices(dependencies)
+	if err != nil {
+		return nil, err
+	}
+	if len(serviceNames) == 0 {
+		return orderedServices, nil
+	}
+
+	selectedServices := make(map[string]bool, len(serviceNames))
+	for _, serviceName := range serviceNames {
+		selectedServices[serviceName] = true
+	}
+	order := make([]string, 0, len(selectedServices))
+	for _, serviceName := range orderedServices {
+		if selectedServices[serviceName] == true {
+			order = append(order, serviceName)
+		}
+	}
+	log.Debugf("Return start order: %v", order)
+
+	return order, nil
+}
+
+// ServicesStopOrder permit to get the order to stop the services, the services are stopped before their required services
+// It's the reverse of start order
+// It return error if service not found, if there are cycle in dependencies or if something wrong when it call the API
+func (c *AmbariClient) ServicesStopOrder(clusterName string, serviceNames []string) ([]string, error) {
+
+	startOrder, err := c.ServicesStartOrder(clusterName, serviceNames)
+	if err != nil {
+		return nil, err
+	}
+	order := make([]string, 0, len(startOrder))
+	for i := len(startOrder) - 1; i >= 0; i-- {
+		order = append(order, startOrder[i])
+	}
+	log.Debugf("Return stop order: %v", order)
+
+	return order, nil
+}
+
+// StartServicesInOrder permit to start the services one by one in the order of their dependencies
+// It wait each service is started before start the next one
+// If disableMaintenanceMode is set to true, it will disable maintenance state before start each service
+// It return error if one service can't be started or if something wrong when it call the API
+func (c *AmbariClient) StartServicesInOrder(clusterName string, serviceNames []string, disableMaintenanceMode bool) error {
+
+	order, err := c.ServicesStartOrder(clusterName, serviceNames)
+	if err != nil {
+		return err
+	}
+	for _, serviceName := range order {
+		log.Infof("Start service %s", serviceName)
+		_, err = c.StartService(clusterName, serviceName, disableMaintenanceMode)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StopServicesInOrder permit to stop the services one by one in the reverse order of their dependencies
+// It wait each service is stopped before stop the next one
+// If enableMaintenanceMode is set to true, it will enable maintenance state after stopped each service
+// If force is set to true, It will disable maintenance state before stop each service
+// It return error if one service can't be stopped or if something wrong when it call the API
+func (c *AmbariClient) StopServicesInOrder(clusterName string, serviceNames []string, enableMaintenanceMode bool, force bool) error {
+
+	order, err := c.ServicesStopOrder(clusterName, serviceNames)
+	if err != nil {
+		return err
+	}
+	for _, serviceName := range order {
+		log.Infof("Stop service %s", serviceName)
+		_, err = c.StopService(clusterName, serviceName, enableMaintenanceMode, force)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sortServices permit to sort services with topological sort, the required services are first
+// The services without dependency between them are sorted by name to always have the same order
+// It return error if there are cycle in dependencies
+func sortServices(dependencies map[string][]string) ([]string, error) {
+
+	nbDependencies := make(map[string]int, len(dependencies))
+	dependents := make(map[string][]string, len(dependencies))
+	for serviceName, requiredServices := range dependencies {
+		nbDependencies[serviceName] = len(requiredServices)
+		for _, requiredService := range requiredServices {
+			dependents[requiredService] = append(dependents[requiredService], serviceName)
+		}
+	}
+
+	readyServices := make([]string, 0, len(dependencies))
+	for serviceName, nb := range nbDependencies {
+		if nb == 0 {
+			readyServices = append(readyServices, serviceName)
+		}
+	}
+
+	order := make([]string, 0, len(dependencies))
+	for len(readyServices) > 0 {
+		sort.Strings(readyServices)
+		serviceName := readyServices[0]
+		readyServices = readyServices[1:]
+		order = append(order, serviceName)
+		for _, dependent := range dependents[serviceName] {
+			nbDependencies[dependent]--
+			if nbDependencies[dependent] == 0 {
+				readyServices = append(readyServices, dependent)
+			}
+		}
+	}
+
+	if len(order) != len(dependencies) {
+		cycle := make([]string, 0)
+		for serviceName, nb := range nbDependencies {
+			if nb > 0 {
+				cycle = append(cycle, serviceName)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, NewAmbariError(500, "There are cycle in dependencies between services %s", strings.Join(cycle, ","))
+	}
+
+	return order, nil
+}
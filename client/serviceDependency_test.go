@@ -0,0 +1,48 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestServiceDependency() {
+
+	// Sort services
+	order, err := sortServices(map[string][]string{
+		"HIVE":         []string{"HDFS", "YARN", "ZOOKEEPER"},
+		"YARN":         []string{"HDFS"},
+		"HDFS":         []string{"ZOOKEEPER"},
+		"ZOOKEEPER":    []string{},
+		"AMBARI_INFRA": []string{},
+	})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"AMBARI_INFRA", "ZOOKEEPER", "HDFS", "YARN", "HIVE"}, order)
+
+	// Sort services with cycle
+	_, err = sortServices(map[string][]string{
+		"A": []string{"B"},
+		"B": []string{"A"},
+		"C": []string{},
+	})
+	assert.Error(s.T(), err)
+
+	// Get dependencies
+	dependencies, err := s.client.ServiceDependencies("test")
+	assert.NoError(s.T(), err)
+	assert.Contains(s.T(), dependencies, "ZOOKEEPER")
+
+	// Get start and stop order
+	order, err = s.client.ServicesStartOrder("test", []string{"ZOOKEEPER"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"ZOOKEEPER"}, order)
+	order, err = s.client.ServicesStopOrder("test", nil)
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), order)
+	_, err = s.client.ServicesStartOrder("test", []string{"FAKE"})
+	assert.Error(s.T(), err)
+
+	// Stop and start services in order
+	err = s.client.StopServicesInOrder("test", []string{"ZOOKEEPER"}, false, false)
+	assert.NoError(s.T(), err)
+	err = s.client.StartServicesInOrder("test", []string{"ZOOKEEPER"}, false)
+	assert.NoError(s.T(), err)
+}
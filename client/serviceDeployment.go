@@ -0,0 +1,309 @@
+// This file permit to add new service on cluster in one call: service, components, configurations and host components
+// It do the same steps as Ambari UI when you add service
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ServiceSpec describe the service to add on cluster
+// Components is the list of hosts for each component of service
+type ServiceSpec struct {
+	ServiceName    string              `json:"service_name"`
+	RepositoryId   int                 `json:"repository_id,omitempty"`
+	Configurations []Configuration     `json:"configurations,omitempty"`
+	Components     map[string][]string `json:"components"`
+	Start          bool                `json:"start,omitempty"`
+}
+
+// String permit to get service spec object as Json string
+func (s *ServiceSpec) String() string {
+	json, _ := json.Marshal(s)
+	return string(json)
+}
+
+// AddService permit to add service on cluster from spec
+// It create the service, the components, the configurations and the host components if they not exist, then it install the service and start it if needed.
+// It can be run again on existing service: only the new host components are installed and started, the running components are not stopped.
+// If something wrong, it remove what it created and it restore the previous version of configurations it changed (the new configuration versions are kept because of Ambari can't remove them)
+// It return the service if all work fine
+// It return error if something wrong when it call the API
+func (c *AmbariClient) AddService(clusterName string, spec *ServiceSpec) (*Service, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	if spec == nil {
+		panic("Spec can't be nil")
+	}
+	if spec.ServiceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debugf("Spec: %s", spec)
+
+	rollback := &serviceRollback{
+		clusterName:    clusterName,
+		serviceName:    spec.ServiceName,
+		hostComponents: make(map[string][]string),
+		configurations: make(map[string]string),
+	}
+	service, err := c.addService(clusterName, spec, rollback)
+	if err != nil {
+		log.Errorf("Failed to add service %s, remove what is created: %s", spec.ServiceName, err.Error())
+		errRollback := rollback.Run(c)
+		if errRollback != nil {
+			log.Errorf("Failed to remove service %s: %s", spec.ServiceName, errRollback.Error())
+		}
+		return nil, err
+	}
+
+	return service, nil
+}
+
+func (c *AmbariClient) addService(clusterName string, spec *ServiceSpec, rollback *serviceRollback) (*Service, error) {
+
+	// Create the service if needed
+	service, err := c.Service(clusterName, spec.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	if service == nil {
+		service = &Service{
+			ServiceInfo: &ServiceInfo{
+				ClusterName:  clusterName,
+				ServiceName:  spec.ServiceName,
+				RepositoryId: spec.RepositoryId,
+			},
+		}
+		service, err = c.CreateService(service)
+		if err != nil {
+			return nil, err
+		}
+		if service == nil {
+			return nil, NewAmbariError(500, "Can't get service %s that just created", spec.ServiceName)
+		}
+		rollback.isServiceCreated = true
+		log.Debugf("Service %s is created", spec.ServiceName)
+	} else {
+		log.Debugf("Service %s already exist", spec.ServiceName)
+	}
+
+	// Create the components if needed
+	componentNames := make([]string, 0, len(spec.Components))
+	for componentName := range spec.Components {
+		componentNames = append(componentNames, componentName)
+	}
+	sort.Strings(componentNames)
+	for _, componentName := range componentNames {
+		component, err := c.Component(clusterName, spec.ServiceName, componentName)
+		if err != nil {
+			return nil, err
+		}
+		if component == nil {
+			component = &Component{
+				ComponentInfo: &ComponentInfo{
+					ClusterName:   clusterName,
+					ServiceName:   spec.ServiceName,
+					ComponentName: componentName,
+				},
+			}
+			_, err = c.CreateComponent(component)
+			if err != nil {
+				return nil, err
+			}
+			rollback.components = append(rollback.components, componentName)
+			log.Debugf("Component %s is created", componentName)
+		} else {
+			log.Debugf("Component %s already exist", componentName)
+		}
+	}
+
+	// Create the configurations if they not exist or if they change
+	if len(spec.Configurations) > 0 {
+		desiredConfigurations, err := c.DesiredConfigurations(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		t := time.Now()
+		tag := fmt.Sprintf("version_%s", t.Format("2006-01-02_15:04:05"))
+		for _, configuration := range spec.Configurations {
+			var currentConfiguration *Configuration
+			if desiredConfiguration, isFound := desiredConfigurations[configuration.Type]; isFound {
+				currentConfiguration = &desiredConfiguration
+			}
+			if len(currentConfiguration.Diff(&configuration)) == 0 {
+				log.Debugf("Configuration %s is up to date", configuration.Type)
+				continue
+			}
+			if configuration.Tag == "" {
+				configuration.Tag = tag
+			}
+			_, err = c.CreateConfigurationOnCluster(clusterName, &configuration)
+			if err != nil {
+				return nil, err
+			}
+			if currentConfiguration != nil {
+				rollback.configurations[configuration.Type] = currentConfiguration.Tag
+			}
+			log.Debugf("Configuration %s is created with tag %s", configuration.Type, configuration.Tag)
+		}
+	}
+
+	// Create the host components if needed
+	for _, componentName := range componentNames {
+		for _, hostname := range spec.Components[componentName] {
+			hostComponent, err := c.HostComponent(clusterName, hostname, componentName)
+			if err != nil {
+				return nil, err
+			}
+			if hostComponent != nil {
+				log.Debugf("Component %s is already associated to host %s", componentName, hostname)
+				continue
+			}
+			hostComponent = &HostComponent{
+				HostComponentInfo: &HostComponentInfo{
+					ClusterName:   clusterName,
+					ServiceName:   spec.ServiceName,
+					ComponentName: componentName,
+					Hostname:      hostname,
+				},
+			}
+			_, err = c.CreateHostComponent(hostComponent)
+			if err != nil {
+				return nil, err
+			}
+			rollback.hostComponents[componentName] = append(rollback.hostComponents[componentName], hostname)
+			log.Debugf("Component %s is associated to host %s", componentName, hostname)
+		}
+	}
+
+	// Install the service if it's not yet installed. Else only the new host components are installed, so the running components are not stopped
+	service, err = c.Service(clusterName, spec.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	if service == nil {
+		return nil, NewAmbariError(404, "Service %s not found in cluster %s", spec.ServiceName, clusterName)
+	}
+	isNewService := service.ServiceInfo.State == SERVICE_INIT || service.ServiceInfo.State == SERVICE_INSTALL_FAILED
+	if isNewService {
+		service, err = c.InstallService(service)
+		if err != nil {
+			return nil, err
+		}
+		log.Debugf("Service %s is installed", spec.ServiceName)
+	} else {
+		for _, componentName := range componentNames {
+			hostnames := rollback.hostComponents[componentName]
+			if len(hostnames) == 0 {
+				continue
+			}
+			_, err = c.SetHostComponentsState(clusterName, &HostComponentsQuery{
+				ComponentNames: []string{componentName},
+				Hostnames:      hostnames,
+			}, SERVICE_INSTALLED, fmt.Sprintf("Install %s on %s from API", componentName, strings.Join(hostnames, ",")))
+			if err != nil {
+				return nil, err
+			}
+			log.Debugf("Component %s is installed on %s", componentName, strings.Join(hostnames, ","))
+		}
+	}
+
+	// Start the service if needed
+	if spec.Start == true {
+		// The service can be already started, so start the new host components (except clients) before
+		if isNewService == false {
+			for _, componentName := range componentNames {
+				hostnames := rollback.hostComponents[componentName]
+				if len(hostnames) == 0 {
+					continue
+				}
+				_, err = c.SetHostComponentsState(clusterName, &HostComponentsQuery{
+					ComponentNames: []string{componentName},
+					Hostnames:      hostnames,
+					Categories:     []string{COMPONENT_MASTER, COMPONENT_SLAVE},
+				}, SERVICE_STARTED, fmt.Sprintf("Start %s on %s from API", componentName, strings.Join(hostnames, ",")))
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		service, err = c.StartService(clusterName, spec.ServiceName, false)
+		if err != nil {
+			return nil, err
+		}
+		log.Debugf("Service %s is started", spec.ServiceName)
+	}
+
+	log.Debugf("Return service: %s", service)
+
+	return service, nil
+}
+
+// serviceRollback keep what is created when add service to remove it if something wrong
+type serviceRollback struct {
+	clusterName      string
+	serviceName      string
+	isServiceCreated bool
+	components       []string
+	hostComponents   map[string][]string
+	configurations   map[string]string
+}
+
+// Run permit to remove what is created
+// It restore the previous tag of the configurations that are changed.
+// If the service is created, it remove the service with all components. If not, it remove only the created components and host components.
+func (r *serviceRollback) Run(c *AmbariClient) error {
+
+	configurationTypes := make([]string, 0, len(r.configurations))
+	for configurationType := range r.configurations {
+		configurationTypes = append(configurationTypes, configurationType)
+	}
+	sort.Strings(configurationTypes)
+	for _, configurationType := range configurationTypes {
+		log.Debugf("Restore configuration %s with tag %s", configurationType, r.configurations[configurationType])
+		_, err := c.CreateConfigurationOnCluster(r.clusterName, &Configuration{
+			Type: configurationType,
+			Tag:  r.configurations[configurationType],
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.isServiceCreated == true {
+		log.Debugf("Remove service %s", r.serviceName)
+		return c.DeleteService(r.clusterName, r.serviceName)
+	}
+
+	createdComponents := make(map[string]bool, len(r.components))
+	for _, componentName := range r.components {
+		createdComponents[componentName] = true
+		log.Debugf("Remove component %s", componentName)
+		err := c.DeleteComponent(r.clusterName, r.serviceName, componentName)
+		if err != nil {
+			return err
+		}
+	}
+	for componentName, hostnames := range r.hostComponents {
+		if createdComponents[componentName] == true {
+			continue
+		}
+		for _, hostname := range hostnames {
+			log.Debugf("Remove component %s on host %s", componentName, hostname)
+			err := c.DeleteHostComponent(r.clusterName, hostname, componentName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestServiceDeployment() {
+
+	// Add service that already exist, it must keep running
+	spec := &ServiceSpec{
+		ServiceName: "ZOOKEEPER",
+		Components: map[string][]string{
+			"ZOOKEEPER_CLIENT": []string{"ambari-agent2"},
+		},
+	}
+	service, err := s.client.AddService("test", spec)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), service)
+	if service != nil {
+		assert.Equal(s.T(), "ZOOKEEPER", service.ServiceInfo.ServiceName)
+		assert.Equal(s.T(), SERVICE_STARTED, service.ServiceInfo.State)
+	}
+
+	// Add service that failed, it must be removed
+	spec = &ServiceSpec{
+		ServiceName: "SQOOP",
+		Components: map[string][]string{
+			"FAKE": []string{"ambari-agent2"},
+		},
+	}
+	service, err = s.client.AddService("test", spec)
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), service)
+	service, err = s.client.Service("test", "SQOOP")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), service)
+}
@@ -0,0 +1,143 @@
+// This file permit to get a health summary of each service in cluster
+// It aggregate the service state, the components state, the stale configs and the alerts
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ALERT_CRITICAL = "CRITICAL"
+	ALERT_WARNING  = "WARNING"
+)
+
+// ServiceHealth is the health summary of service
+type ServiceHealth struct {
+	ServiceName      string            `json:"service_name"`
+	State            string            `json:"state"`
+	MaintenanceState string            `json:"maintenance_state"`
+	Components       []ComponentHealth `json:"components"`
+	StaleConfigs     int               `json:"stale_configs"`
+	CriticalAlerts   []Alert           `json:"critical_alerts"`
+	WarningAlerts    []Alert           `json:"warning_alerts"`
+}
+
+// ComponentHealth is the health summary of component on all hosts
+type ComponentHealth struct {
+	ComponentName string `json:"component_name"`
+	Category      string `json:"category"`
+	Started       int    `json:"started"`
+	Installed     int    `json:"installed"`
+	Total         int    `json:"total"`
+	StaleConfigs  int    `json:"stale_configs"`
+}
+
+// String permit to return ServiceHealth object as Json string
+func (s *ServiceHealth) String() string {
+	json, _ := json.Marshal(s)
+	return string(json)
+}
+
+// IsHealthy permit to know if the service is healthy
+// The service is healthy if it's started, if all components (except clients) are started on all hosts and if there are no critical alert
+func (s *ServiceHealth) IsHealthy() bool {
+	if s.State != SERVICE_STARTED || len(s.CriticalAlerts) > 0 {
+		return false
+	}
+	for _, component := range s.Components {
+		if component.Category != COMPONENT_CLIENT && component.Started < component.Total {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ServiceHealth permit to get the health summary of all services in cluster
+// The alerts of service, component or host in maintenance state are not counted
+// It return the health of each service
+// It return error if something wrong when it call the API
+func (c *AmbariClient) ServiceHealth(clusterName string) ([]ServiceHealth, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+
+	// Get the services with the components counters
+	services, err := c.Services(clusterName, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the host components with stale configs
+	path := fmt.Sprintf("/clusters/%s/host_components", clusterName)
+	resp, err := c.Client().R().SetQueryParam("HostRoles/stale_configs", "true").SetQueryParam("fields", "HostRoles/service_name,HostRoles/component_name,HostRoles/host_name,HostRoles/stale_configs").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	hostComponents := &HostComponents{}
+	err = json.Unmarshal(resp.Body(), hostComponents)
+	if err != nil {
+		return nil, err
+	}
+	staleConfigs := make(map[string]int)
+	for _, hostComponent := range hostComponents.Items {
+		if hostComponent.HostComponentInfo.StaleConfigs == true {
+			staleConfigs[hostComponent.HostComponentInfo.ComponentName]++
+		}
+	}
+
+	// Get the alerts
+	alerts, err := c.AlertsInCluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Aggregate all of them by service
+	servicesHealth := make([]ServiceHealth, 0, len(services))
+	for _, service := range services {
+		serviceHealth := ServiceHealth{
+			ServiceName:      service.ServiceInfo.ServiceName,
+			State:            service.ServiceInfo.State,
+			MaintenanceState: service.ServiceInfo.MaintenanceState,
+			Components:       make([]ComponentHealth, 0, len(service.Components)),
+			CriticalAlerts:   make([]Alert, 0),
+			WarningAlerts:    make([]Alert, 0),
+		}
+		for _, component := range service.Components {
+			componentHealth := ComponentHealth{
+				ComponentName: component.ComponentInfo.ComponentName,
+				Category:      component.ComponentInfo.Category,
+				Started:       component.ComponentInfo.StartedCount,
+				Installed:     component.ComponentInfo.InstalledCount,
+				Total:         component.ComponentInfo.TotalCount,
+				StaleConfigs:  staleConfigs[component.ComponentInfo.ComponentName],
+			}
+			serviceHealth.StaleConfigs += componentHealth.StaleConfigs
+			serviceHealth.Components = append(serviceHealth.Components, componentHealth)
+		}
+		for _, alert := range alerts {
+			if alert.AlertInfo.ServiceName != service.ServiceInfo.ServiceName {
+				continue
+			}
+			switch alert.AlertInfo.State {
+			case ALERT_CRITICAL:
+				serviceHealth.CriticalAlerts = append(serviceHealth.CriticalAlerts, alert)
+			case ALERT_WARNING:
+				serviceHealth.WarningAlerts = append(serviceHealth.WarningAlerts, alert)
+			}
+		}
+		servicesHealth = append(servicesHealth, serviceHealth)
+	}
+	log.Debugf("Return health of %d services", len(servicesHealth))
+
+	return servicesHealth, nil
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestServiceHealth() {
+
+	// Get health of all services
+	servicesHealth, err := s.client.ServiceHealth("test")
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), servicesHealth)
+	isFound := false
+	for _, serviceHealth := range servicesHealth {
+		if serviceHealth.ServiceName == "ZOOKEEPER" {
+			isFound = true
+			assert.NotEmpty(s.T(), serviceHealth.State)
+			assert.NotEmpty(s.T(), serviceHealth.Components)
+			for _, componentHealth := range serviceHealth.Components {
+				assert.True(s.T(), componentHealth.Total > 0)
+			}
+		}
+	}
+	assert.True(s.T(), isFound)
+
+	// Check healthy
+	serviceHealth := &ServiceHealth{
+		State: SERVICE_STARTED,
+		Components: []ComponentHealth{
+			ComponentHealth{Category: COMPONENT_MASTER, Started: 3, Total: 3},
+			ComponentHealth{Category: COMPONENT_CLIENT, Started: 0, Installed: 2, Total: 2},
+		},
+	}
+	assert.True(s.T(), serviceHealth.IsHealthy())
+	serviceHealth.Components[0].Started = 2
+	assert.False(s.T(), serviceHealth.IsHealthy())
+	serviceHealth.Components[0].Started = 3
+	serviceHealth.CriticalAlerts = []Alert{Alert{AlertInfo: &AlertInfo{State: ALERT_CRITICAL}}}
+	assert.False(s.T(), serviceHealth.IsHealthy())
+}
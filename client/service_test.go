@@ -35,6 +35,34 @@ func (s *ClientTestSuite) TestService() {
 		assert.Equal(s.T(), 0, len(service.Components))
 	}
 
+	// Get all services
+	services, err := s.client.Services("test", "", "")
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), services)
+	isFound := false
+	for _, item := range services {
+		if item.ServiceInfo.ServiceName == "ZOOKEEPER" {
+			isFound = true
+			assert.NotEmpty(s.T(), item.ServiceInfo.State)
+			assert.NotEmpty(s.T(), item.Components)
+		}
+	}
+	assert.True(s.T(), isFound)
+
+	// Get services filtered by state
+	services, err = s.client.Services("test", SERVICE_STARTED, MAINTENANCE_STATE_OFF)
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), services)
+	isFound = false
+	for _, item := range services {
+		assert.Equal(s.T(), SERVICE_STARTED, item.ServiceInfo.State)
+		assert.Equal(s.T(), MAINTENANCE_STATE_OFF, item.ServiceInfo.MaintenanceState)
+		if item.ServiceInfo.ServiceName == "ZOOKEEPER" {
+			isFound = true
+		}
+	}
+	assert.True(s.T(), isFound)
+
 	// Stop service
 	service, err = s.client.StopService("test", "ZOOKEEPER", false, false)
 	assert.NoError(s.T(), err)
@@ -51,6 +79,33 @@ func (s *ClientTestSuite) TestService() {
 		assert.Equal(s.T(), SERVICE_STARTED, service.ServiceInfo.State)
 	}
 
+	// Restart service
+	err = s.client.RestartService("test", "ZOOKEEPER")
+	assert.NoError(s.T(), err)
+
+	// Restart component
+	err = s.client.RestartComponent("test", "ZOOKEEPER", "ZOOKEEPER_SERVER", nil)
+	assert.NoError(s.T(), err)
+	err = s.client.RestartComponent("test", "ZOOKEEPER", "FAKE", nil)
+	assert.Error(s.T(), err)
+	err = s.client.RestartComponent("test", "ZOOKEEPER", "ZOOKEEPER_SERVER", []string{"ambari-agent2"})
+	assert.NoError(s.T(), err)
+	err = s.client.RestartComponent("test", "ZOOKEEPER", "ZOOKEEPER_SERVER", []string{"fake"})
+	assert.Error(s.T(), err)
+	err = s.client.RestartComponent("test", "ZOOKEEPER", "ZOOKEEPER_CLIENT", nil)
+	assert.Error(s.T(), err)
+
+	// Run service check
+	serviceCheck, err := s.client.RunServiceCheck("test", "ZOOKEEPER")
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), serviceCheck)
+	if serviceCheck != nil {
+		assert.True(s.T(), serviceCheck.IsPassed())
+		assert.NotEmpty(s.T(), serviceCheck.Tasks)
+	}
+	assert.Equal(s.T(), "ZOOKEEPER_QUORUM_SERVICE_CHECK", ServiceCheckCommand("ZOOKEEPER"))
+	assert.Equal(s.T(), "HDFS_SERVICE_CHECK", ServiceCheckCommand("HDFS"))
+
 	// Stop all services
 	cluster, err := s.client.Cluster("test")
 	if err != nil {
@@ -73,13 +128,64 @@ func (s *ClientTestSuite) TestService() {
 	}
 	assert.Equal(s.T(), SERVICE_STARTED, service.ServiceInfo.State)
 
+	// Keep the configurations of ZOOKEEPER to add it again after delete it
+	configurationTypes, err := s.client.serviceConfigurationTypes("test", "ZOOKEEPER", nil)
+	if err != nil {
+		panic(err)
+	}
+	desiredConfigurations, err := s.client.DesiredConfigurations("test")
+	if err != nil {
+		panic(err)
+	}
+	configurations := make([]Configuration, 0, len(configurationTypes))
+	for _, configurationType := range configurationTypes {
+		if configuration, isFound := desiredConfigurations[configurationType]; isFound {
+			configurations = append(configurations, configuration)
+		}
+	}
+
 	// Delete service
+	err = s.client.DeleteService("test", "FAKE")
+	assert.Error(s.T(), err)
+	kafka, err := s.client.CreateService(&Service{
+		ServiceInfo: &ServiceInfo{
+			ClusterName:  "test",
+			ServiceName:  "KAFKA",
+			RepositoryId: 1,
+		},
+	})
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), kafka)
+	err = s.client.DeleteServiceWithCheck("test", "ZOOKEEPER", false)
+	assert.Error(s.T(), err)
+	if ambariError, ok := err.(AmbariError); ok {
+		assert.Equal(s.T(), 409, ambariError.Code)
+	}
+	err = s.client.DeleteServiceWithCheck("test", "KAFKA", false)
+	assert.NoError(s.T(), err)
 	err = s.client.DeleteService("test", "ZOOKEEPER")
 	assert.NoError(s.T(), err)
 	service, err = s.client.Service("test", "ZOOKEEPER")
 	assert.NoError(s.T(), err)
 	assert.Nil(s.T(), service)
 
+	// Add ZOOKEEPER again, the other tests need it
+	service, err = s.client.AddService("test", &ServiceSpec{
+		ServiceName:    "ZOOKEEPER",
+		RepositoryId:   1,
+		Configurations: configurations,
+		Components: map[string][]string{
+			"ZOOKEEPER_SERVER": []string{"ambari-agent2"},
+			"ZOOKEEPER_CLIENT": []string{"ambari-agent2", "ambari-agent3"},
+		},
+		Start: true,
+	})
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), service)
+	if service != nil {
+		assert.Equal(s.T(), SERVICE_STARTED, service.ServiceInfo.State)
+	}
+
 	// Install service (we can't install service without add components and host components)
 	// @TODO
 
@@ -0,0 +1,332 @@
+// Ambari documentation: https://github.com/apache/ambari/blob/trunk/ambari-server/docs/api/v1/stack-resources.md
+// This file permit to browse the stack metadata (services, components and configurations provided by stack version)
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+// Stack service object
+type StackService struct {
+	StackServiceInfo *StackServiceInfo `json:"StackServices"`
+}
+type StackServices struct {
+	Items []StackService `json:"items,omitempty"`
+}
+type StackServiceInfo struct {
+	StackName        string                 `json:"stack_name,omitempty"`
+	StackVersion     string                 `json:"stack_version,omitempty"`
+	ServiceName      string                 `json:"service_name,omitempty"`
+	DisplayName      string                 `json:"display_name,omitempty"`
+	ServiceVersion   string                 `json:"service_version,omitempty"`
+	Comments         string                 `json:"comments,omitempty"`
+	RequiredServices []string               `json:"required_services,omitempty"`
+	CustomCommands   []string               `json:"custom_commands,omitempty"`
+	ConfigTypes      map[string]interface{} `json:"config_types,omitempty"`
+}
+
+// Stack component object
+type StackComponent struct {
+	StackComponentInfo *StackComponentInfo `json:"StackServiceComponents"`
+	Dependencies       []StackDependency   `json:"dependencies,omitempty"`
+}
+type StackComponents struct {
+	Items []StackComponent `json:"items,omitempty"`
+}
+type StackComponentInfo struct {
+	StackName           string   `json:"stack_name,omitempty"`
+	StackVersion        string   `json:"stack_version,omitempty"`
+	ServiceName         string   `json:"service_name,omitempty"`
+	ComponentName       string   `json:"component_name,omitempty"`
+	DisplayName         string   `json:"display_name,omitempty"`
+	Category            string   `json:"component_category,omitempty"`
+	Cardinality         string   `json:"cardinality,omitempty"`
+	IsMaster            bool     `json:"is_master,omitempty"`
+	IsClient            bool     `json:"is_client,omitempty"`
+	DecommissionAllowed bool     `json:"decommission_allowed,omitempty"`
+	CustomCommands      []string `json:"custom_commands,omitempty"`
+}
+type StackDependency struct {
+	StackDependencyInfo *StackDependencyInfo `json:"Dependencies"`
+}
+type StackDependencyInfo struct {
+	ServiceName            string `json:"service_name,omitempty"`
+	ComponentName          string `json:"component_name,omitempty"`
+	DependentServiceName   string `json:"dependent_service_name,omitempty"`
+	DependentComponentName string `json:"dependent_component_name,omitempty"`
+	Scope                  string `json:"scope,omitempty"`
+}
+
+// Stack configuration object
+type StackConfiguration struct {
+	StackConfigurationInfo *StackConfigurationInfo `json:"StackConfigurations"`
+}
+type StackConfigurations struct {
+	Items []StackConfiguration `json:"items,omitempty"`
+}
+type StackConfigurationInfo struct {
+	StackName               string                        `json:"stack_name,omitempty"`
+	StackVersion            string                        `json:"stack_version,omitempty"`
+	ServiceName             string                        `json:"service_name,omitempty"`
+	Type                    string                        `json:"type,omitempty"`
+	PropertyName            string                        `json:"property_name,omitempty"`
+	PropertyValue           string                        `json:"property_value,omitempty"`
+	PropertyDisplayName     string                        `json:"property_display_name,omitempty"`
+	PropertyDescription     string                        `json:"property_description,omitempty"`
+	PropertyType            []string                      `json:"property_type,omitempty"`
+	PropertyValueAttributes *StackPropertyValueAttributes `json:"property_value_attributes,omitempty"`
+}
+type StackPropertyValueAttributes struct {
+	Type            string `json:"type,omitempty"`
+	Unit            string `json:"unit,omitempty"`
+	Minimum         string `json:"minimum,omitempty"`
+	Maximum         string `json:"maximum,omitempty"`
+	Overridable     bool   `json:"overridable,omitempty"`
+	Visible         bool   `json:"visible,omitempty"`
+	EmptyValueValid bool   `json:"empty_value_valid,omitempty"`
+}
+
+// String permit to return StackService object as Json string
+func (s *StackService) String() string {
+	json, _ := json.Marshal(s)
+	return string(json)
+}
+
+// String permit to return StackComponent object as Json string
+func (s *StackComponent) String() string {
+	json, _ := json.Marshal(s)
+	return string(json)
+}
+
+// String permit to return StackConfiguration object as Json string
+func (s *StackConfiguration) String() string {
+	json, _ := json.Marshal(s)
+	return string(json)
+}
+
+// ConfigurationType permit to get the configuration type of property (the file name without .xml extension, like hdfs-site)
+func (s *StackConfiguration) ConfigurationType() string {
+	return strings.TrimSuffix(s.StackConfigurationInfo.Type, ".xml")
+}
+
+// IsPassword permit to know if the property has PASSWORD type
+func (s *StackConfiguration) IsPassword() bool {
+	for _, propertyType := range s.StackConfigurationInfo.PropertyType {
+		if propertyType == PROPERTY_TYPE_PASSWORD {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StackServices permit to get all services provided by stack version
+// It return the list of services
+// It return error if something wrong when it call the API
+func (c *AmbariClient) StackServices(stackName string, stackVersion string) ([]StackService, error) {
+
+	if stackName == "" {
+		panic("StackName can't be empty")
+	}
+	if stackVersion == "" {
+		panic("StackVersion can't be empty")
+	}
+	log.Debug("StackName: ", stackName)
+	log.Debug("StackVersion: ", stackVersion)
+
+	path := fmt.Sprintf("/stacks/%s/versions/%s/services", stackName, stackVersion)
+	resp, err := c.Client().R().SetQueryParam("fields", "StackServices/*").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			return nil, nil
+		} else {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+	}
+	stackServices := &StackServices{}
+	err = json.Unmarshal(resp.Body(), stackServices)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return %d stack services", len(stackServices.Items))
+
+	return stackServices.Items, nil
+}
+
+// StackService permit to get one service provided by stack version
+// It return the service if found
+// It return nil if service not found
+// It return error if something wrong when it call the API
+func (c *AmbariClient) StackService(stackName string, stackVersion string, serviceName string) (*StackService, error) {
+
+	if stackName == "" {
+		panic("StackName can't be empty")
+	}
+	if stackVersion == "" {
+		panic("StackVersion can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	log.Debug("StackName: ", stackName)
+	log.Debug("StackVersion: ", stackVersion)
+	log.Debug("ServiceName: ", serviceName)
+
+	path := fmt.Sprintf("/stacks/%s/versions/%s/services/%s", stackName, stackVersion, serviceName)
+	resp, err := c.Client().R().SetQueryParam("fields", "StackServices/*").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			return nil, nil
+		} else {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+	}
+	stackService := &StackService{}
+	err = json.Unmarshal(resp.Body(), stackService)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return stack service: %s", stackService)
+
+	return stackService, nil
+}
+
+// StackComponents permit to get all components of service provided by stack version, with their dependencies
+// It return the list of components
+// It return error if something wrong when it call the API
+func (c *AmbariClient) StackComponents(stackName string, stackVersion string, serviceName string) ([]StackComponent, error) {
+
+	if stackName == "" {
+		panic("StackName can't be empty")
+	}
+	if stackVersion == "" {
+		panic("StackVersion can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	log.Debug("StackName: ", stackName)
+	log.Debug("StackVersion: ", stackVersion)
+	log.Debug("ServiceName: ", serviceName)
+
+	path := fmt.Sprintf("/stacks/%s/versions/%s/services/%s/components", stackName, stackVersion, serviceName)
+	resp, err := c.Client().R().SetQueryParam("fields", "StackServiceComponents/*,dependencies/Dependencies/*").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			return nil, nil
+		} else {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+	}
+	stackComponents := &StackComponents{}
+	err = json.Unmarshal(resp.Body(), stackComponents)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return %d stack components", len(stackComponents.Items))
+
+	return stackComponents.Items, nil
+}
+
+// StackComponent permit to get one component of service provided by stack version, with its dependencies
+// It return the component if found
+// It return nil if component not found
+// It return error if something wrong when it call the API
+func (c *AmbariClient) StackComponent(stackName string, stackVersion string, serviceName string, componentName string) (*StackComponent, error) {
+
+	if stackName == "" {
+		panic("StackName can't be empty")
+	}
+	if stackVersion == "" {
+		panic("StackVersion can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	if componentName == "" {
+		panic("ComponentName can't be empty")
+	}
+	log.Debug("StackName: ", stackName)
+	log.Debug("StackVersion: ", stackVersion)
+	log.Debug("ServiceName: ", serviceName)
+	log.Debug("ComponentName: ", componentName)
+
+	path := fmt.Sprintf("/stacks/%s/versions/%s/services/%s/components/%s", stackName, stackVersion, serviceName, componentName)
+	resp, err := c.Client().R().SetQueryParam("fields", "StackServiceComponents/*,dependencies/Dependencies/*").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			return nil, nil
+		} else {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+	}
+	stackComponent := &StackComponent{}
+	err = json.Unmarshal(resp.Body(), stackComponent)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return stack component: %s", stackComponent)
+
+	return stackComponent, nil
+}
+
+// StackConfigurations permit to get all configuration properties of service provided by stack version, with their default value and type
+// It return the list of configuration properties
+// It return error if something wrong when it call the API
+func (c *AmbariClient) StackConfigurations(stackName string, stackVersion string, serviceName string) ([]StackConfiguration, error) {
+
+	if stackName == "" {
+		panic("StackName can't be empty")
+	}
+	if stackVersion == "" {
+		panic("StackVersion can't be empty")
+	}
+	if serviceName == "" {
+		panic("ServiceName can't be empty")
+	}
+	log.Debug("StackName: ", stackName)
+	log.Debug("StackVersion: ", stackVersion)
+	log.Debug("ServiceName: ", serviceName)
+
+	path := fmt.Sprintf("/stacks/%s/versions/%s/services/%s/configurations", stackName, stackVersion, serviceName)
+	resp, err := c.Client().R().SetQueryParam("fields", "StackConfigurations/*").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			return nil, nil
+		} else {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+	}
+	stackConfigurations := &StackConfigurations{}
+	err = json.Unmarshal(resp.Body(), stackConfigurations)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return %d stack configurations", len(stackConfigurations.Items))
+
+	return stackConfigurations.Items, nil
+}
@@ -0,0 +1,323 @@
+// Ambari documentation: https://cwiki.apache.org/confluence/display/AMBARI/Stack+Advisor
+// This file permit to call the stack advisor to get recommendations and validations about configurations and layout
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RECOMMEND_CONFIGURATIONS             = "configurations"
+	RECOMMEND_HOST_GROUPS                = "host_groups"
+	RECOMMEND_CONFIGURATION_DEPENDENCIES = "configuration-dependencies"
+	VALIDATE_CONFIGURATIONS              = "configurations"
+	VALIDATE_HOST_GROUPS                 = "host_groups"
+	VALIDATION_LEVEL_ERROR               = "ERROR"
+	VALIDATION_LEVEL_WARN                = "WARN"
+)
+
+// Request objects
+type RecommendationRequest struct {
+	Recommend             string                 `json:"recommend"`
+	Hosts                 []string               `json:"hosts"`
+	Services              []string               `json:"services"`
+	ChangedConfigurations []ChangedConfiguration `json:"changed_configurations,omitempty"`
+	Recommendations       *StackLayout           `json:"recommendations,omitempty"`
+}
+type ValidationRequest struct {
+	Validate        string       `json:"validate"`
+	Hosts           []string     `json:"hosts"`
+	Services        []string     `json:"services"`
+	Recommendations *StackLayout `json:"recommendations"`
+}
+type ChangedConfiguration struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	OldValue string `json:"old_value"`
+}
+
+// Layout object used on request and response
+type StackLayout struct {
+	Blueprint               *StackLayoutBlueprint `json:"blueprint,omitempty"`
+	BlueprintClusterBinding *StackLayoutBinding   `json:"blueprint_cluster_binding,omitempty"`
+}
+type StackLayoutBlueprint struct {
+	Configurations map[string]StackLayoutConfiguration `json:"configurations,omitempty"`
+	HostGroups     []StackLayoutHostGroup              `json:"host_groups,omitempty"`
+}
+type StackLayoutConfiguration struct {
+	Properties         map[string]string                 `json:"properties,omitempty"`
+	PropertyAttributes map[string]map[string]interface{} `json:"property_attributes,omitempty"`
+}
+type StackLayoutBinding struct {
+	HostGroups []StackLayoutHostGroup `json:"host_groups,omitempty"`
+}
+type StackLayoutHostGroup struct {
+	Name       string              `json:"name"`
+	Components []map[string]string `json:"components,omitempty"`
+	Hosts      []map[string]string `json:"hosts,omitempty"`
+}
+
+// Response objects
+type RecommendationResponse struct {
+	Resources []Recommendation `json:"resources"`
+}
+type Recommendation struct {
+	RecommendationInfo *RecommendationInfo `json:"Recommendation"`
+	Hosts              []string            `json:"hosts,omitempty"`
+	Services           []string            `json:"services,omitempty"`
+	Recommendations    *StackLayout        `json:"recommendations,omitempty"`
+}
+type RecommendationInfo struct {
+	Id int `json:"id,omitempty"`
+}
+type ValidationResponse struct {
+	Resources []Validation `json:"resources"`
+}
+type Validation struct {
+	ValidationInfo *ValidationInfo  `json:"Validation"`
+	Items          []ValidationItem `json:"items,omitempty"`
+}
+type ValidationInfo struct {
+	Id int `json:"id,omitempty"`
+}
+type ValidationItem struct {
+	Type          string `json:"type,omitempty"`
+	Level         string `json:"level,omitempty"`
+	Message       string `json:"message,omitempty"`
+	ConfigType    string `json:"config-type,omitempty"`
+	ConfigName    string `json:"config-name,omitempty"`
+	ComponentName string `json:"component-name,omitempty"`
+	Host          string `json:"host,omitempty"`
+}
+
+// String permit to return Recommendation object as Json string
+func (r *Recommendation) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// String permit to return Validation object as Json string
+func (v *Validation) String() string {
+	json, _ := json.Marshal(v)
+	return string(json)
+}
+
+// Errors permit to get only the validation items with ERROR level
+func (v *Validation) Errors() []ValidationItem {
+	return v.filterItems(VALIDATION_LEVEL_ERROR)
+}
+
+// Warnings permit to get only the validation items with WARN level
+func (v *Validation) Warnings() []ValidationItem {
+	return v.filterItems(VALIDATION_LEVEL_WARN)
+}
+
+func (v *Validation) filterItems(level string) []ValidationItem {
+	items := make([]ValidationItem, 0, 0)
+	for _, item := range v.Items {
+		if item.Level == level {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// StackLayout permit to convert blueprint to layout usable by the stack advisor
+// The hosts is the list of hosts for each host group of blueprint
+func (b *Blueprint) StackLayout(hosts map[string][]string) *StackLayout {
+
+	layout := &StackLayout{
+		Blueprint: &StackLayoutBlueprint{
+			Configurations: make(map[string]StackLayoutConfiguration),
+			HostGroups:     make([]StackLayoutHostGroup, 0, len(b.HostGroups)),
+		},
+		BlueprintClusterBinding: &StackLayoutBinding{
+			HostGroups: make([]StackLayoutHostGroup, 0, len(b.HostGroups)),
+		},
+	}
+
+	for _, configurations := range b.Configurations {
+		for configurationType, configuration := range configurations {
+			layout.Blueprint.Configurations[configurationType] = StackLayoutConfiguration{
+				Properties: configuration["properties"],
+			}
+		}
+	}
+
+	for _, hostGroup := range b.HostGroups {
+		layout.Blueprint.HostGroups = append(layout.Blueprint.HostGroups, StackLayoutHostGroup{
+			Name:       hostGroup.Name,
+			Components: hostGroup.Components,
+		})
+		binding := StackLayoutHostGroup{
+			Name:  hostGroup.Name,
+			Hosts: make([]map[string]string, 0, len(hosts[hostGroup.Name])),
+		}
+		for _, hostname := range hosts[hostGroup.Name] {
+			binding.Hosts = append(binding.Hosts, map[string]string{"fqdn": hostname})
+		}
+		layout.BlueprintClusterBinding.HostGroups = append(layout.BlueprintClusterBinding.HostGroups, binding)
+	}
+
+	return layout
+}
+
+// Recommendations permit to ask the stack advisor to recommend configurations or layout
+// It return the recommendation if all work fine
+// It return error if something wrong when it call the API
+func (c *AmbariClient) Recommendations(stackName string, stackVersion string, request *RecommendationRequest) (*Recommendation, error) {
+
+	if stackName == "" {
+		panic("StackName can't be empty")
+	}
+	if stackVersion == "" {
+		panic("StackVersion can't be empty")
+	}
+	if request == nil {
+		panic("Request can't be nil")
+	}
+	log.Debug("StackName: ", stackName)
+	log.Debug("StackVersion: ", stackVersion)
+
+	path := fmt.Sprintf("/stacks/%s/versions/%s/recommendations", stackName, stackVersion)
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	// The request is not logged because of the configurations can contain passwords
+	log.Debugf("Recommend %s for services %v on hosts %v", request.Recommend, request.Services, request.Hosts)
+	resp, err := c.Client().R().SetBody(jsonData).Post(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to recommend: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	recommendationResponse := &RecommendationResponse{}
+	err = json.Unmarshal(resp.Body(), recommendationResponse)
+	if err != nil {
+		return nil, err
+	}
+	if len(recommendationResponse.Resources) == 0 {
+		return nil, NewAmbariError(500, "Stack advisor don't return recommendation")
+	}
+	recommendation := &recommendationResponse.Resources[0]
+	log.Debugf("Return recommendation: %s", recommendation)
+
+	return recommendation, nil
+}
+
+// Validations permit to ask the stack advisor to validate configurations or layout
+// It return the validation with all items (errors and warnings) if all work fine
+// It return error if something wrong when it call the API
+func (c *AmbariClient) Validations(stackName string, stackVersion string, request *ValidationRequest) (*Validation, error) {
+
+	if stackName == "" {
+		panic("StackName can't be empty")
+	}
+	if stackVersion == "" {
+		panic("StackVersion can't be empty")
+	}
+	if request == nil {
+		panic("Request can't be nil")
+	}
+	log.Debug("StackName: ", stackName)
+	log.Debug("StackVersion: ", stackVersion)
+
+	path := fmt.Sprintf("/stacks/%s/versions/%s/validations", stackName, stackVersion)
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	// The request is not logged because of the configurations can contain passwords
+	log.Debugf("Validate %s for services %v on hosts %v", request.Validate, request.Services, request.Hosts)
+	resp, err := c.Client().R().SetBody(jsonData).Post(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to validate: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	validationResponse := &ValidationResponse{}
+	err = json.Unmarshal(resp.Body(), validationResponse)
+	if err != nil {
+		return nil, err
+	}
+	if len(validationResponse.Resources) == 0 {
+		return nil, NewAmbariError(500, "Stack advisor don't return validation")
+	}
+	validation := &validationResponse.Resources[0]
+	log.Debugf("Return validation: %s", validation)
+
+	return validation, nil
+}
+
+// ClusterLayout permit to get the current layout of cluster usable by the stack advisor
+// It create one host group per host with all components hosted on it, and add the desired configurations
+// It return error if cluster not found or if something wrong when it call the API
+func (c *AmbariClient) ClusterLayout(clusterName string) (*StackLayout, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+
+	configurations, err := c.DesiredConfigurations(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/clusters/%s/hosts", clusterName)
+	resp, err := c.Client().R().SetQueryParam("fields", "Hosts/host_name,host_components/HostRoles/component_name").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+	}
+	hosts := &Hosts{}
+	err = json.Unmarshal(resp.Body(), hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := &StackLayout{
+		Blueprint: &StackLayoutBlueprint{
+			Configurations: make(map[string]StackLayoutConfiguration),
+			HostGroups:     make([]StackLayoutHostGroup, 0, len(hosts.Items)),
+		},
+		BlueprintClusterBinding: &StackLayoutBinding{
+			HostGroups: make([]StackLayoutHostGroup, 0, len(hosts.Items)),
+		},
+	}
+	for configurationType, configuration := range configurations {
+		layout.Blueprint.Configurations[configurationType] = StackLayoutConfiguration{
+			Properties: configuration.Properties,
+		}
+	}
+	for index, host := range hosts.Items {
+		hostGroupName := fmt.Sprintf("host-group-%d", index+1)
+		hostGroup := StackLayoutHostGroup{
+			Name:       hostGroupName,
+			Components: make([]map[string]string, 0, len(host.HostComponents)),
+		}
+		for _, hostComponent := range host.HostComponents {
+			hostGroup.Components = append(hostGroup.Components, map[string]string{"name": hostComponent.HostComponentInfo.ComponentName})
+		}
+		layout.Blueprint.HostGroups = append(layout.Blueprint.HostGroups, hostGroup)
+		layout.BlueprintClusterBinding.HostGroups = append(layout.BlueprintClusterBinding.HostGroups, StackLayoutHostGroup{
+			Name:  hostGroupName,
+			Hosts: []map[string]string{map[string]string{"fqdn": host.HostInfo.Hostname}},
+		})
+	}
+
+	return layout, nil
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestStackAdvisor() {
+
+	// Get cluster layout
+	layout, err := s.client.ClusterLayout("test")
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), layout)
+	if layout != nil {
+		assert.NotEmpty(s.T(), layout.Blueprint.HostGroups)
+		assert.Equal(s.T(), len(layout.Blueprint.HostGroups), len(layout.BlueprintClusterBinding.HostGroups))
+		_, isFound := layout.Blueprint.Configurations["zoo.cfg"]
+		assert.True(s.T(), isFound)
+	}
+
+	// Get recommendations
+	hosts := make([]string, 0, 1)
+	for _, hostGroup := range layout.BlueprintClusterBinding.HostGroups {
+		hosts = append(hosts, hostGroup.Hosts[0]["fqdn"])
+	}
+	recommendation, err := s.client.Recommendations("HDP", "2.6", &RecommendationRequest{
+		Recommend:       RECOMMEND_CONFIGURATIONS,
+		Hosts:           hosts,
+		Services:        []string{"ZOOKEEPER"},
+		Recommendations: layout,
+	})
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), recommendation)
+	if recommendation != nil {
+		assert.NotNil(s.T(), recommendation.Recommendations)
+	}
+
+	// Get validations
+	validation, err := s.client.Validations("HDP", "2.6", &ValidationRequest{
+		Validate:        VALIDATE_CONFIGURATIONS,
+		Hosts:           hosts,
+		Services:        []string{"ZOOKEEPER"},
+		Recommendations: layout,
+	})
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), validation)
+	if validation != nil {
+		assert.Empty(s.T(), validation.Errors())
+	}
+
+	// Convert blueprint to layout
+	blueprint, err := s.client.Blueprint("test")
+	if err != nil {
+		panic(err)
+	}
+	layout = blueprint.StackLayout(map[string][]string{
+		"host_group_1": []string{"ambari-agent2"},
+	})
+	assert.Equal(s.T(), len(blueprint.HostGroups), len(layout.Blueprint.HostGroups))
+	assert.Equal(s.T(), "ambari-agent2", layout.BlueprintClusterBinding.HostGroups[0].Hosts[0]["fqdn"])
+
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *ClientTestSuite) TestStack() {
+
+	// Get stack services
+	stackServices, err := s.client.StackServices("HDP", "2.6")
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), stackServices)
+
+	// Get stack service
+	stackService, err := s.client.StackService("HDP", "2.6", "HBASE")
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), stackService)
+	if stackService != nil {
+		assert.Equal(s.T(), "HBASE", stackService.StackServiceInfo.ServiceName)
+		assert.Contains(s.T(), stackService.StackServiceInfo.RequiredServices, "ZOOKEEPER")
+	}
+	stackService, err = s.client.StackService("HDP", "2.6", "FAKE")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), stackService)
+
+	// Get stack components
+	stackComponents, err := s.client.StackComponents("HDP", "2.6", "ZOOKEEPER")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, len(stackComponents))
+
+	// Get stack component
+	stackComponent, err := s.client.StackComponent("HDP", "2.6", "ZOOKEEPER", "ZOOKEEPER_SERVER")
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), stackComponent)
+	if stackComponent != nil {
+		assert.Equal(s.T(), COMPONENT_MASTER, stackComponent.StackComponentInfo.Category)
+		assert.Equal(s.T(), "1+", stackComponent.StackComponentInfo.Cardinality)
+	}
+
+	// Get stack configurations
+	stackConfigurations, err := s.client.StackConfigurations("HDP", "2.6", "ZOOKEEPER")
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), stackConfigurations)
+	isFound := false
+	for _, stackConfiguration := range stackConfigurations {
+		if stackConfiguration.StackConfigurationInfo.PropertyName == "clientPort" {
+			isFound = true
+			assert.Equal(s.T(), "zoo.cfg", stackConfiguration.ConfigurationType())
+			assert.Equal(s.T(), "2181", stackConfiguration.StackConfigurationInfo.PropertyValue)
+		}
+	}
+	assert.True(s.T(), isFound)
+
+}
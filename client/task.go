@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"strings"
 	"time"
 )
 
@@ -15,6 +16,10 @@ const (
 	REQUEST_ACCEPTED  = "ACCEPTED"
 	REQUEST_COMPLETED = "COMPLETED"
 	REQUEST_ABORDED   = "ABORDED"
+	TASK_COMPLETED    = "COMPLETED"
+	TASK_FAILED       = "FAILED"
+	TASK_ABORTED      = "ABORTED"
+	TASK_TIMEDOUT     = "TIMEDOUT"
 )
 
 type RequestTask struct {
@@ -37,6 +42,38 @@ type RequestsTask struct {
 	Items []RequestTask `json:"Items"`
 }
 
+// Task object, it's one command run on one host by the request
+type Task struct {
+	TaskInfo *TaskInfo `json:"Tasks"`
+}
+type Tasks struct {
+	Items []Task `json:"items,omitempty"`
+}
+type TaskInfo struct {
+	Id            int    `json:"id,omitempty"`
+	RequestId     int    `json:"request_id,omitempty"`
+	ClusterName   string `json:"cluster_name,omitempty"`
+	Hostname      string `json:"host_name,omitempty"`
+	Role          string `json:"role,omitempty"`
+	Command       string `json:"command,omitempty"`
+	CommandDetail string `json:"command_detail,omitempty"`
+	Status        string `json:"status,omitempty"`
+	ExitCode      int    `json:"exit_code,omitempty"`
+	Stdout        string `json:"stdout,omitempty"`
+	Stderr        string `json:"stderr,omitempty"`
+}
+
+// String permit to get Task object as Json string
+func (t *Task) String() string {
+	json, _ := json.Marshal(t)
+	return string(json)
+}
+
+// IsFailed permit to know if the task is failed, aborted or timed out
+func (t *Task) IsFailed() bool {
+	return t.TaskInfo.Status == TASK_FAILED || t.TaskInfo.Status == TASK_ABORTED || t.TaskInfo.Status == TASK_TIMEDOUT
+}
+
 // String permit to get Request object as Json string
 func (r *RequestTask) String() string {
 	json, _ := json.Marshal(r)
@@ -46,7 +83,15 @@ func (r *RequestTask) String() string {
 // Permit to wait the rerquest task is finished
 // It can return error if API call failed
 func (r *RequestTask) Wait(c *AmbariClient, clusterName string) error {
+	return r.WaitWithTimeout(c, clusterName, 0)
+}
+
+// WaitWithTimeout permit to wait the request task is finished, but no more than timeout
+// If timeout is 0, it wait without limit
+// It return error if API call failed or if the request is not finished before the timeout
+func (r *RequestTask) WaitWithTimeout(c *AmbariClient, clusterName string, timeout time.Duration) error {
 	if r.RequestTaskInfo != nil {
+		start := time.Now()
 		isRun := true
 		for isRun {
 			requestTask, err := c.Request(clusterName, r.RequestTaskInfo.Id)
@@ -55,6 +100,9 @@ func (r *RequestTask) Wait(c *AmbariClient, clusterName string) error {
 			}
 			*r = *requestTask
 			if r.RequestTaskInfo.ProgressPercent < 100 {
+				if timeout > 0 && time.Since(start) > timeout {
+					return NewAmbariError(408, "Request %d is not finished after %s, state is %s (%f %%)", r.RequestTaskInfo.Id, timeout, r.RequestTaskInfo.Status, r.RequestTaskInfo.ProgressPercent)
+				}
 				log.Debugf("Task '%s' (%d) is not yet finished, state is %s (%f %%)", r.RequestTaskInfo.Context, r.RequestTaskInfo.Id, r.RequestTaskInfo.Status, r.RequestTaskInfo.ProgressPercent)
 				time.Sleep(10 * time.Second)
 			} else {
@@ -147,3 +195,71 @@ func (c *AmbariClient) Requests(clusterName string) ([]RequestTask, error) {
 
 	return requestsTask.Items, nil
 }
+
+// Tasks permit to get all tasks of request with their output
+// It return the list of tasks
+// It return error if something wrong with the API call
+func (c *AmbariClient) Tasks(clusterName string, requestId int) ([]Task, error) {
+
+	if clusterName == "" {
+		panic("ClusterName can't be empty")
+	}
+	log.Debug("ClusterName: ", clusterName)
+	log.Debug("RequestId: ", requestId)
+
+	path := fmt.Sprintf("/clusters/%s/requests/%d/tasks", clusterName, requestId)
+	resp, err := c.Client().R().SetQueryParam("fields", "Tasks/*").Get(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Response to get: ", resp)
+	if resp.StatusCode() >= 300 {
+		if resp.StatusCode() == 404 {
+			return nil, nil
+		} else {
+			return nil, NewAmbariError(resp.StatusCode(), resp.Status())
+		}
+	}
+	tasks := &Tasks{}
+	err = json.Unmarshal(resp.Body(), tasks)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Return %d tasks", len(tasks.Items))
+
+	return tasks.Items, nil
+}
+
+// FailedTasks permit to get the tasks of request that are failed, aborted or timed out
+// It return error if something wrong with the API call
+func (r *RequestTask) FailedTasks(c *AmbariClient, clusterName string) ([]Task, error) {
+
+	tasks, err := c.Tasks(clusterName, r.RequestTaskInfo.Id)
+	if err != nil {
+		return nil, err
+	}
+	failedTasks := make([]Task, 0, 0)
+	for _, task := range tasks {
+		if task.IsFailed() {
+			failedTasks = append(failedTasks, task)
+		}
+	}
+
+	return failedTasks, nil
+}
+
+// Error permit to get the error of failed request with the host and the stderr of each failed task
+func (r *RequestTask) Error(c *AmbariClient, clusterName string) error {
+
+	message := fmt.Sprintf("Request %d failed with status %s, task completed %d, task aborded %d, task failed %d", r.RequestTaskInfo.Id, r.RequestTaskInfo.Status, r.RequestTaskInfo.CompletedTask, r.RequestTaskInfo.AbordedTask, r.RequestTaskInfo.FailedTask)
+	failedTasks, err := r.FailedTasks(c, clusterName)
+	if err != nil {
+		log.Warnf("Can't get the failed tasks of request %d: %s", r.RequestTaskInfo.Id, err.Error())
+		return NewAmbariError(500, "%s", message)
+	}
+	for _, task := range failedTasks {
+		message = fmt.Sprintf("%s\n%s on %s is %s: %s", message, task.TaskInfo.Role, task.TaskInfo.Hostname, task.TaskInfo.Status, strings.TrimSpace(task.TaskInfo.Stderr))
+	}
+
+	return NewAmbariError(500, "%s", message)
+}
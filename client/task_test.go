@@ -2,6 +2,7 @@ package client
 
 import (
 	"github.com/stretchr/testify/assert"
+	"time"
 )
 
 func (s *ClientTestSuite) TestTask() {
@@ -24,7 +25,29 @@ func (s *ClientTestSuite) TestTask() {
 	assert.NoError(s.T(), err)
 	assert.NotEmpty(s.T(), requestsTask)
 
+	// Get tasks of request created by the test
+	serviceCheck, err := s.client.RunServiceCheck("test", "ZOOKEEPER")
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), serviceCheck)
+	if serviceCheck != nil {
+		tasks, err := s.client.Tasks("test", serviceCheck.RequestId)
+		assert.NoError(s.T(), err)
+		assert.NotEmpty(s.T(), tasks)
+		assert.Equal(s.T(), len(serviceCheck.Tasks), len(tasks))
+		serviceCheckTask, err := s.client.Request("test", serviceCheck.RequestId)
+		assert.NoError(s.T(), err)
+		assert.NotNil(s.T(), serviceCheckTask)
+		if serviceCheckTask != nil {
+			assert.Equal(s.T(), len(tasks), serviceCheckTask.RequestTaskInfo.TaskCount)
+			failedTasks, err := serviceCheckTask.FailedTasks(s.client, "test")
+			assert.NoError(s.T(), err)
+			assert.Empty(s.T(), failedTasks)
+		}
+	}
+
 	// Wait task is finished
 	err = requestTask.Wait(s.client, "test")
 	assert.NoError(s.T(), err)
+	err = requestTask.WaitWithTimeout(s.client, "test", 1*time.Minute)
+	assert.NoError(s.T(), err)
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/urfave/cli.v1"
+	"io/ioutil"
+)
+
+func downloadClientConfig(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("service-name") == "" {
+		return cli.NewExitError("You must set service-name parameter", 1)
+	}
+
+	data, err := clientAmbari.DownloadClientConfig(c.String("cluster-name"), c.String("service-name"), c.String("component-name"), c.String("extract-directory"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Save the tarball if needed. If nothing is set, it's saved in current directory like the Ambari UI does
+	outputFile := c.String("output-file")
+	if outputFile == "" && c.String("extract-directory") == "" {
+		if c.String("component-name") != "" {
+			outputFile = fmt.Sprintf("%s-configs.tar.gz", c.String("component-name"))
+		} else {
+			outputFile = fmt.Sprintf("%s_CLIENT-configs.tar.gz", c.String("service-name"))
+		}
+	}
+	if outputFile != "" {
+		err = ioutil.WriteFile(outputFile, data, 0644)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		log.Infof("Successfully download client configurations of %s in %s", c.String("service-name"), outputFile)
+	}
+	if c.String("extract-directory") != "" {
+		log.Infof("Successfully extract client configurations of %s in %s", c.String("service-name"), c.String("extract-directory"))
+	}
+
+	return nil
+}
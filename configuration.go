@@ -0,0 +1,577 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/disaster37/go-ambari-rest/client"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/urfave/cli.v1"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type ConfigurationFile struct {
+	Type                 string                       `json:"type" yaml:"type"`
+	Properties           map[string]string            `json:"properties" yaml:"properties"`
+	PropertiesAttributes map[string]map[string]string `json:"properties_attributes,omitempty" yaml:"properties_attributes,omitempty"`
+}
+
+// Configuration permit to convert configuration file to configuration
+func (f *ConfigurationFile) Configuration() *client.Configuration {
+	return &client.Configuration{
+		Type:                 f.Type,
+		Properties:           f.Properties,
+		PropertiesAttributes: f.PropertiesAttributes,
+	}
+}
+
+// newConfigurationFile permit to convert configuration to configuration file
+func newConfigurationFile(configuration *client.Configuration) *ConfigurationFile {
+	return &ConfigurationFile{
+		Type:                 configuration.Type,
+		Properties:           configuration.Properties,
+		PropertiesAttributes: configuration.PropertiesAttributes,
+	}
+}
+
+// The prefix of property value that reference a secret instead of plaintext value
+const (
+	SECRET_ENV_PREFIX  = "${env:"
+	SECRET_FILE_PREFIX = "${file:"
+)
+
+func exportConfiguration(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("directory") == "" {
+		return cli.NewExitError("You must set directory parameter", 1)
+	}
+	if c.String("format") != "json" && c.String("format") != "yaml" {
+		return cli.NewExitError("The format parameter must be json or yaml", 1)
+	}
+
+	// Get all desired configurations
+	configurations, err := clientAmbari.DesiredConfigurations(c.String("cluster-name"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Get the password properties to mask them
+	passwordProperties, err := clientAmbari.PasswordProperties(c.String("cluster-name"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Write one file per configuration type
+	err = os.MkdirAll(c.String("directory"), 0755)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	for configurationType, configuration := range configurations {
+		configuration.MarkPasswordProperties(passwordProperties)
+		if c.Bool("unmask-passwords") == false {
+			configuration = *configuration.Masked()
+		}
+		configuration.Type = configurationType
+		configurationFile := newConfigurationFile(&configuration)
+		fileName := filepath.Join(c.String("directory"), fmt.Sprintf("%s.%s", configurationType, c.String("format")))
+		err = writeConfigurationFile(fileName, configurationFile)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		log.Debugf("Configuration %s is exported on %s", configurationType, fileName)
+	}
+
+	log.Infof("Successfully export %d configurations of cluster %s in %s", len(configurations), c.String("cluster-name"), c.String("directory"))
+
+	return nil
+}
+
+func applyConfiguration(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("directory") == "" {
+		return cli.NewExitError("You must set directory parameter", 1)
+	}
+
+	// Read the configuration files
+	configurationFiles, err := loadConfigurationFiles(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Get the current configurations and resolve the secrets referenced on configuration files
+	configurations, err := clientAmbari.DesiredConfigurations(c.String("cluster-name"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	err = resolveSecrets(configurationFiles, configurations)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	passwordProperties, err := clientAmbari.PasswordProperties(c.String("cluster-name"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Validate the configurations with the stack advisor if needed
+	if c.Bool("validate") == true {
+		validation, err := validateConfigurationFiles(clientAmbari, c.String("cluster-name"), configurationFiles)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		err = displayValidation(validation)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+
+	// Compute the plan and apply it if needed
+	t := time.Now()
+	tag := fmt.Sprintf("version_%s", t.Format("2006-01-02_15:04:05"))
+	nbChanges := 0
+	for _, configurationFile := range configurationFiles {
+		targetConfiguration := configurationFile.Configuration()
+		targetConfiguration.Tag = tag
+		targetConfiguration.MarkPasswordProperties(passwordProperties)
+
+		var currentConfiguration *client.Configuration
+		if configuration, isFound := configurations[configurationFile.Type]; isFound {
+			currentConfiguration = &configuration
+			currentConfiguration.MarkPasswordProperties(passwordProperties)
+			if targetConfiguration.PropertiesAttributes == nil {
+				targetConfiguration.PropertiesAttributes = configuration.PropertiesAttributes
+			}
+		}
+
+		changes := currentConfiguration.Diff(targetConfiguration)
+		if len(changes) == 0 {
+			log.Infof("Configuration %s is up to date", configurationFile.Type)
+			continue
+		}
+		nbChanges++
+		log.Infof("Configuration %s has %d changes:", configurationFile.Type, len(changes))
+		for _, change := range changes {
+			log.Info(formatPropertyChange(change))
+		}
+
+		if c.Bool("dry-run") == false {
+			_, err = clientAmbari.CreateConfigurationOnCluster(c.String("cluster-name"), targetConfiguration)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			log.Infof("Configuration %s is updated with tag %s", configurationFile.Type, tag)
+		}
+	}
+
+	if c.Bool("dry-run") == true {
+		log.Infof("Dry run: %d configurations need to be updated in cluster %s", nbChanges, c.String("cluster-name"))
+	} else {
+		log.Infof("Successfully apply %d configurations in cluster %s", nbChanges, c.String("cluster-name"))
+	}
+
+	return nil
+}
+
+func validateConfiguration(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("directory") == "" {
+		return cli.NewExitError("You must set directory parameter", 1)
+	}
+
+	// Read the configuration files and resolve the secrets
+	configurationFiles, err := loadConfigurationFiles(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	configurations, err := clientAmbari.DesiredConfigurations(c.String("cluster-name"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	err = resolveSecrets(configurationFiles, configurations)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Validate them
+	validation, err := validateConfigurationFiles(clientAmbari, c.String("cluster-name"), configurationFiles)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	err = displayValidation(validation)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Configurations are valid for cluster %s", c.String("cluster-name"))
+
+	return nil
+}
+
+func renderConfiguration(c *cli.Context) error {
+
+	if c.String("directory") == "" {
+		return cli.NewExitError("You must set directory parameter", 1)
+	}
+	if c.String("format") != "json" && c.String("format") != "yaml" {
+		return cli.NewExitError("The format parameter must be json or yaml", 1)
+	}
+
+	// Read, merge and render the configuration files
+	configurationFiles, err := loadConfigurationFiles(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Display the final configurations
+	for _, configurationFile := range configurationFiles {
+		var b []byte
+		if c.String("format") == "json" {
+			b, err = json.MarshalIndent(configurationFile, "", "  ")
+		} else {
+			b, err = yaml.Marshal(configurationFile)
+		}
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if c.String("format") == "yaml" {
+			fmt.Println("---")
+		}
+		fmt.Println(string(b))
+	}
+
+	log.Infof("Successfully render %d configurations", len(configurationFiles))
+
+	return nil
+}
+
+// loadConfigurationFiles permit to read the configuration files, merge the overlays and render the variables
+// The overlay directories are merged in the order they are set, so the last one win
+// The variables are read from the variables file, and overwritten by the variables set on command line
+func loadConfigurationFiles(c *cli.Context) ([]ConfigurationFile, error) {
+
+	configurationFiles, err := readConfigurationFiles(c.String("directory"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge the overlays
+	for _, overlayDirectory := range c.StringSlice("overlay") {
+		overlayFiles, err := readConfigurationFiles(overlayDirectory)
+		if err != nil {
+			return nil, err
+		}
+		for _, overlayFile := range overlayFiles {
+			isFound := false
+			for index, configurationFile := range configurationFiles {
+				if configurationFile.Type == overlayFile.Type {
+					configuration := configurationFile.Configuration().Merge(overlayFile.Configuration())
+					configurationFiles[index] = *newConfigurationFile(configuration)
+					isFound = true
+					break
+				}
+			}
+			if isFound == false {
+				configurationFiles = append(configurationFiles, overlayFile)
+			}
+			log.Debugf("Overlay %s is merged from %s", overlayFile.Type, overlayDirectory)
+		}
+	}
+
+	// Render the variables
+	variables, err := readVariables(c.String("vars-file"), c.StringSlice("var"))
+	if err != nil {
+		return nil, err
+	}
+	if len(variables) == 0 {
+		return configurationFiles, nil
+	}
+	for index, configurationFile := range configurationFiles {
+		configuration, err := configurationFile.Configuration().Render(variables)
+		if err != nil {
+			return nil, err
+		}
+		configurationFiles[index] = *newConfigurationFile(configuration)
+	}
+
+	return configurationFiles, nil
+}
+
+// readVariables permit to read the variables used to render the configuration files
+// The variables file can be json or yaml. The variables set on command line have the format name=value.
+func readVariables(fileName string, vars []string) (map[string]interface{}, error) {
+
+	variables := make(map[string]interface{})
+	if fileName != "" {
+		b, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		if filepath.Ext(fileName) == ".json" {
+			err = json.Unmarshal(b, &variables)
+		} else {
+			err = yaml.Unmarshal(b, &variables)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Can't read variables file %s", fileName)
+		}
+	}
+
+	for _, variable := range vars {
+		data := strings.SplitN(variable, "=", 2)
+		if len(data) != 2 {
+			return nil, errors.Errorf("The variable %s must have the format name=value", variable)
+		}
+		variables[data[0]] = data[1]
+	}
+	// The values are not logged because of they can contain secrets
+	log.Debugf("Read %d variables", len(variables))
+
+	return variables, nil
+}
+
+// validateConfigurationFiles permit to validate the configuration files with the stack advisor
+// It use the current cluster layout and the current configurations overwritten by the configuration files
+func validateConfigurationFiles(clientAmbari *client.AmbariClient, clusterName string, configurationFiles []ConfigurationFile) (*client.Validation, error) {
+
+	cluster, err := clientAmbari.Cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, client.NewAmbariError(404, "Cluster %s not found", clusterName)
+	}
+	stackName, stackVersion := cluster.StackNameAndVersion()
+
+	layout, err := clientAmbari.ClusterLayout(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	for _, configurationFile := range configurationFiles {
+		layout.Blueprint.Configurations[configurationFile.Type] = client.StackLayoutConfiguration{
+			Properties: configurationFile.Properties,
+		}
+	}
+
+	request := &client.ValidationRequest{
+		Validate:        client.VALIDATE_CONFIGURATIONS,
+		Hosts:           make([]string, 0, len(layout.BlueprintClusterBinding.HostGroups)),
+		Services:        make([]string, 0, len(cluster.Services)),
+		Recommendations: layout,
+	}
+	for _, hostGroup := range layout.BlueprintClusterBinding.HostGroups {
+		for _, host := range hostGroup.Hosts {
+			request.Hosts = append(request.Hosts, host["fqdn"])
+		}
+	}
+	for _, service := range cluster.Services {
+		request.Services = append(request.Services, service.ServiceInfo.ServiceName)
+	}
+
+	return clientAmbari.Validations(stackName, stackVersion, request)
+}
+
+// displayValidation permit to display the warnings and errors returned by the stack advisor
+// It return error if there are some errors
+func displayValidation(validation *client.Validation) error {
+
+	for _, item := range validation.Warnings() {
+		log.Warn(formatValidationItem(item))
+	}
+	validationErrors := validation.Errors()
+	for _, item := range validationErrors {
+		log.Error(formatValidationItem(item))
+	}
+	if len(validationErrors) > 0 {
+		return client.NewAmbariError(400, "The stack advisor found %d errors", len(validationErrors))
+	}
+
+	return nil
+}
+
+// formatValidationItem permit to display the validation item with the config or host concerned
+func formatValidationItem(item client.ValidationItem) string {
+	if item.ConfigType != "" {
+		return fmt.Sprintf("%s/%s: %s", item.ConfigType, item.ConfigName, item.Message)
+	}
+	if item.ComponentName != "" {
+		return fmt.Sprintf("%s on %s: %s", item.ComponentName, item.Host, item.Message)
+	}
+
+	return item.Message
+}
+
+// resolveSecrets permit to replace the secret references of configuration files by their value
+// The property value can be:
+//   - ${env:NAME} to read the value from environment variable NAME
+//   - ${file:/path/to/file} to read the value from file (the trailing new line is removed)
+//   - ******** (the masked value set by export) to keep the current value on cluster
+//
+// It return error if the environment variable, the file or the current value not exist
+func resolveSecrets(configurationFiles []ConfigurationFile, configurations map[string]client.Configuration) error {
+
+	for _, configurationFile := range configurationFiles {
+		for name, value := range configurationFile.Properties {
+			switch {
+			case strings.HasPrefix(value, SECRET_ENV_PREFIX) && strings.HasSuffix(value, "}"):
+				variable := strings.TrimSuffix(strings.TrimPrefix(value, SECRET_ENV_PREFIX), "}")
+				secret, isFound := os.LookupEnv(variable)
+				if isFound == false {
+					return errors.Errorf("Environment variable %s referenced by %s/%s is not set", variable, configurationFile.Type, name)
+				}
+				configurationFile.Properties[name] = secret
+			case strings.HasPrefix(value, SECRET_FILE_PREFIX) && strings.HasSuffix(value, "}"):
+				fileName := strings.TrimSuffix(strings.TrimPrefix(value, SECRET_FILE_PREFIX), "}")
+				b, err := ioutil.ReadFile(fileName)
+				if err != nil {
+					return errors.Wrapf(err, "Can't read file %s referenced by %s/%s", fileName, configurationFile.Type, name)
+				}
+				configurationFile.Properties[name] = strings.TrimRight(string(b), "\r\n")
+			case value == client.MASKED_VALUE:
+				configuration, isFound := configurations[configurationFile.Type]
+				if isFound == false {
+					return errors.Errorf("Property %s/%s is masked but not exist on cluster", configurationFile.Type, name)
+				}
+				currentValue, isFound := configuration.Properties[name]
+				if isFound == false {
+					return errors.Errorf("Property %s/%s is masked but not exist on cluster", configurationFile.Type, name)
+				}
+				configurationFile.Properties[name] = currentValue
+			}
+		}
+	}
+
+	return nil
+}
+
+// readConfigurationFiles permit to read all configuration files (json or yaml) in directory
+// The files are sorted by name
+func readConfigurationFiles(directory string) ([]ConfigurationFile, error) {
+
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	fileNames := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() == false {
+			fileNames = append(fileNames, file.Name())
+		}
+	}
+	sort.Strings(fileNames)
+
+	configurationFiles := make([]ConfigurationFile, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		configurationFile, err := readConfigurationFile(filepath.Join(directory, fileName))
+		if err != nil {
+			return nil, err
+		}
+		if configurationFile != nil {
+			configurationFiles = append(configurationFiles, *configurationFile)
+		}
+	}
+
+	return configurationFiles, nil
+}
+
+// readConfigurationFile permit to read one configuration file
+// The format is found from the file extension. It return nil if the file is not a json or yaml file.
+// If the type is not set on file, it use the file name without extension
+func readConfigurationFile(fileName string) (*ConfigurationFile, error) {
+
+	extension := filepath.Ext(fileName)
+	if extension != ".json" && extension != ".yaml" && extension != ".yml" {
+		log.Debugf("File %s is not a configuration file, skip it", fileName)
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	// The content is not logged because of it can contain secrets
+	log.Debugf("Read configuration file %s", fileName)
+
+	configurationFile := &ConfigurationFile{}
+	if extension == ".json" {
+		err = json.Unmarshal(b, configurationFile)
+	} else {
+		err = yaml.Unmarshal(b, configurationFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if configurationFile.Type == "" {
+		configurationFile.Type = strings.TrimSuffix(filepath.Base(fileName), extension)
+	}
+	if configurationFile.Properties == nil {
+		configurationFile.Properties = make(map[string]string)
+	}
+
+	return configurationFile, nil
+}
+
+// writeConfigurationFile permit to write configuration file
+// The format is found from the file extension
+func writeConfigurationFile(fileName string, configurationFile *ConfigurationFile) error {
+
+	var b []byte
+	var err error
+	if filepath.Ext(fileName) == ".json" {
+		b, err = json.MarshalIndent(configurationFile, "", "  ")
+	} else {
+		b, err = yaml.Marshal(configurationFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, b, 0644)
+}
+
+// formatPropertyChange permit to display property change in plan
+// The values of password properties are masked
+func formatPropertyChange(change client.PropertyChange) string {
+	if change.IsPassword {
+		if change.OldValue != "" {
+			change.OldValue = client.MASKED_VALUE
+		}
+		if change.NewValue != "" {
+			change.NewValue = client.MASKED_VALUE
+		}
+	}
+	switch change.Action {
+	case client.PROPERTY_ADDED:
+		return fmt.Sprintf("  + %s: '%s'", change.Name, change.NewValue)
+	case client.PROPERTY_REMOVED:
+		return fmt.Sprintf("  - %s: '%s'", change.Name, change.OldValue)
+	default:
+		return fmt.Sprintf("  ~ %s: '%s' => '%s'", change.Name, change.OldValue, change.NewValue)
+	}
+}
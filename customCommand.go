@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/urfave/cli.v1"
+	"strings"
+)
+
+func listCustomCommands(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("service-name") == "" {
+		return cli.NewExitError("You must set service-name parameter", 1)
+	}
+
+	customCommands, err := clientAmbari.CustomCommands(c.String("cluster-name"), c.String("service-name"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Display the custom commands
+	w := newTableWriter()
+	fmt.Fprintln(w, "SERVICE\tCOMPONENT\tCOMMAND")
+	for _, customCommand := range customCommands {
+		componentName := customCommand.ComponentName
+		if componentName == "" {
+			componentName = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", customCommand.ServiceName, componentName, customCommand.Command)
+	}
+	err = w.Flush()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return nil
+}
+
+func runCustomCommand(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("service-name") == "" {
+		return cli.NewExitError("You must set service-name parameter", 1)
+	}
+	if c.String("command") == "" {
+		return cli.NewExitError("You must set command parameter", 1)
+	}
+	hostnames := make([]string, 0)
+	if c.String("hostnames") != "" {
+		hostnames = strings.Split(c.String("hostnames"), ",")
+	}
+	parameters := make(map[string]string)
+	for _, parameter := range c.StringSlice("parameter") {
+		data := strings.SplitN(parameter, "=", 2)
+		if len(data) != 2 {
+			return cli.NewExitError(fmt.Sprintf("The parameter %s must have the format name=value", parameter), 1)
+		}
+		parameters[data[0]] = data[1]
+	}
+
+	// Run the custom command
+	requestTask, err := clientAmbari.ExecuteCustomCommand(c.String("cluster-name"), c.String("service-name"), c.String("component-name"), c.String("command"), hostnames, parameters)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Successfully run custom command %s on service %s in cluster %s (request %d)", c.String("command"), c.String("service-name"), c.String("cluster-name"), requestTask.RequestTaskInfo.Id)
+
+	return nil
+}
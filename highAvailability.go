@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"github.com/disaster37/go-ambari-rest/client"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/urfave/cli.v1"
+	"sort"
+	"strings"
+	"time"
+)
+
+func enableNameNodeHA(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("nameservice") == "" {
+		return cli.NewExitError("You must set nameservice parameter", 1)
+	}
+	if c.String("additional-namenode-host") == "" {
+		return cli.NewExitError("You must set additional-namenode-host parameter", 1)
+	}
+	if c.String("journalnode-hosts") == "" {
+		return cli.NewExitError("You must set journalnode-hosts parameter", 1)
+	}
+
+	spec := &client.NameNodeHASpec{
+		NameserviceId:          c.String("nameservice"),
+		AdditionalNameNodeHost: c.String("additional-namenode-host"),
+		JournalNodeHosts:       strings.Split(c.String("journalnode-hosts"), ","),
+	}
+	err = clientAmbari.EnableNameNodeHA(c.String("cluster-name"), spec)
+	if err != nil {
+		// The user need to run command on NameNode and run again to resume
+		if manualStepError, ok := err.(client.ManualStepError); ok {
+			log.Warnf("Step %s need manual action: %s", manualStepError.Step, manualStepError.Message)
+			return cli.NewExitError(err, 2)
+		}
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Successfully enable NameNode HA with nameservice %s in cluster %s", c.String("nameservice"), c.String("cluster-name"))
+
+	return nil
+}
+
+func enableResourceManagerHA(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("second-host") == "" {
+		return cli.NewExitError("You must set second-host parameter", 1)
+	}
+
+	plan, err := clientAmbari.EnableResourceManagerHA(c.String("cluster-name"), c.String("second-host"), c.Bool("dry-run"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	if c.Bool("dry-run") == true {
+		// Display the steps and the yarn-site properties that will be set
+		w := newTableWriter()
+		fmt.Fprintln(w, "STEP")
+		for _, step := range plan.Steps {
+			fmt.Fprintln(w, step)
+		}
+		err = w.Flush()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if plan.YarnSite != nil {
+			names := make([]string, 0, len(plan.YarnSite.Properties))
+			for name := range plan.YarnSite.Properties {
+				if strings.HasPrefix(name, "yarn.resourcemanager.") {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			w = newTableWriter()
+			fmt.Fprintln(w, "PROPERTY\tVALUE")
+			for _, name := range names {
+				fmt.Fprintf(w, "%s\t%s\n", name, plan.YarnSite.Properties[name])
+			}
+			err = w.Flush()
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+		return nil
+	}
+
+	log.Infof("Successfully enable ResourceManager HA with %s and %s in cluster %s", plan.ResourceManagerHost, plan.AdditionalResourceManagerHost, c.String("cluster-name"))
+
+	return nil
+}
+
+func displayHaStates(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+
+	haStates, err := clientAmbari.HaStates(c.String("cluster-name"), c.String("component-name"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printHaStates(haStates)
+}
+
+func restartActiveMaster(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("component-name") == "" {
+		return cli.NewExitError("You must set component-name parameter", 1)
+	}
+
+	haStates, err := clientAmbari.RestartActiveMaster(c.String("cluster-name"), c.String("component-name"), time.Duration(c.Int64("timeout"))*time.Second)
+	if err != nil {
+		// Display the current layout to know where is the active component
+		if haStates != nil {
+			errPrint := printHaStates(haStates)
+			if errPrint != nil {
+				return errPrint
+			}
+		}
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Successfully restart active %s in cluster %s", c.String("component-name"), c.String("cluster-name"))
+
+	return printHaStates(haStates)
+}
+
+func printHaStates(haStates []client.HaState) error {
+
+	w := newTableWriter()
+	fmt.Fprintln(w, "COMPONENT\tHOST\tSTATE")
+	for _, haState := range haStates {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", haState.ComponentName, haState.Hostname, haState.State)
+	}
+	err := w.Flush()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return nil
+}
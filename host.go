@@ -4,6 +4,7 @@ import (
 	"github.com/disaster37/go-ambari-rest/client"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/urfave/cli.v1"
+	"time"
 )
 
 func addHostInCluster(c *cli.Context) error {
@@ -54,6 +55,65 @@ func addHostInCluster(c *cli.Context) error {
 	return nil
 }
 
+func removeHostInCluster(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("hostname") == "" {
+		return cli.NewExitError("You must set hostname parameter", 1)
+	}
+
+	// Remove host in safe way
+	err = clientAmbari.RemoveHost(c.String("cluster-name"), c.String("hostname"), time.Duration(c.Int64("decommission-timeout"))*time.Second)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Successfully remove host %s from cluster %s", c.String("hostname"), c.String("cluster-name"))
+
+	return nil
+}
+
+func moveMasterComponent(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("component-name") == "" {
+		return cli.NewExitError("You must set component-name parameter", 1)
+	}
+	if c.String("from-host") == "" {
+		return cli.NewExitError("You must set from-host parameter", 1)
+	}
+	if c.String("to-host") == "" {
+		return cli.NewExitError("You must set to-host parameter", 1)
+	}
+
+	// Move the component
+	err = clientAmbari.MoveMasterComponent(c.String("cluster-name"), c.String("component-name"), c.String("from-host"), c.String("to-host"))
+	if err != nil {
+		// The user need to copy the NameNode metadata and run again to resume
+		if manualStepError, ok := err.(client.ManualStepError); ok {
+			log.Warnf("Step %s need manual action: %s", manualStepError.Step, manualStepError.Message)
+			return cli.NewExitError(err, 2)
+		}
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Successfully move %s from %s to %s in cluster %s", c.String("component-name"), c.String("from-host"), c.String("to-host"), c.String("cluster-name"))
+
+	return nil
+}
+
 func stopAllComponentsInHost(c *cli.Context) error {
 
 	clientAmbari, err := manageGlobalParameters()
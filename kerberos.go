@@ -132,50 +132,6 @@ func addKerberos(c *cli.Context) error {
 	}
 	log.Info("All tasks is finished, we can start to enable Kerberos")
 
-	// Add Kerberos service if needed
-	serviceKerberos, err := clientAmbari.Service(c.String("cluster-name"), KERBEROS_SERVICE)
-	if err != nil {
-		return err
-	}
-	if serviceKerberos == nil {
-		// Add kerberos service
-		serviceKerberos = &client.Service{
-			ServiceInfo: &client.ServiceInfo{
-				ClusterName: c.String("cluster-name"),
-				ServiceName: KERBEROS_SERVICE,
-			},
-		}
-		serviceKerberos, err = clientAmbari.CreateService(serviceKerberos)
-		if err != nil {
-			return err
-		}
-		log.Infof("%s service is created", KERBEROS_SERVICE)
-	} else {
-		log.Infof("%s service is already exist", KERBEROS_SERVICE)
-	}
-
-	// Add KERBEROS_CLIENT components if needed
-	componentKerberosClient, err := clientAmbari.Component(c.String("cluster-name"), KERBEROS_SERVICE, KERBEROS_COMPONENT)
-	if err != nil {
-		return err
-	}
-	if componentKerberosClient == nil {
-		componentKerberosClient = &client.Component{
-			ComponentInfo: &client.ComponentInfo{
-				ClusterName:   c.String("cluster-name"),
-				ServiceName:   KERBEROS_SERVICE,
-				ComponentName: KERBEROS_COMPONENT,
-			},
-		}
-		componentKerberosClient, err = clientAmbari.CreateComponent(componentKerberosClient)
-		if err != nil {
-			return err
-		}
-		log.Infof("%s component is created", KERBEROS_COMPONENT)
-	} else {
-		log.Infof("%s component is already exist", KERBEROS_COMPONENT)
-	}
-
 	// Add Kerberos service settings
 	t := time.Now()
 	tag := fmt.Sprintf("version_%s", t.Format("2006-01-02_15:04:05"))
@@ -189,11 +145,6 @@ func addKerberos(c *cli.Context) error {
 			"content":          c.String("krb5-conf-template"),
 		},
 	}
-	_, err = clientAmbari.CreateConfigurationOnCluster(c.String("cluster-name"), configurationKerberosService)
-	if err != nil {
-		return err
-	}
-	log.Info("Setting 'krb-conf' is created in cluster")
 	configurationKerberosEnv := &client.Configuration{
 		Type: "kerberos-env",
 		Tag:  tag,
@@ -224,45 +175,24 @@ func addKerberos(c *cli.Context) error {
 			"preconfigure_services":           c.String("preconfigure-services"),
 		},
 	}
-	_, err = clientAmbari.CreateConfigurationOnCluster(c.String("cluster-name"), configurationKerberosEnv)
-	if err != nil {
-		return err
-	}
-	log.Info("Setting 'kerberos-env' is created in cluster")
 
-	// Add KERBEROS_CLIENT components on all nodes if needed
+	// Add Kerberos service with KERBEROS_CLIENT on all nodes and install it
 	hosts, err := clientAmbari.HostsOnCluster(c.String("cluster-name"))
 	if err != nil {
 		return err
 	}
 	log.Debugf("Found %d hosts in cluster", len(hosts))
+	hostnames := make([]string, 0, len(hosts))
 	for _, host := range hosts {
-		hostComponent, err := clientAmbari.HostComponent(c.String("cluster-name"), host.HostInfo.Hostname, KERBEROS_COMPONENT)
-		if err != nil {
-			return err
-		}
-		if hostComponent == nil {
-			hostComponent := &client.HostComponent{
-				HostComponentInfo: &client.HostComponentInfo{
-					ClusterName:   c.String("cluster-name"),
-					ServiceName:   KERBEROS_SERVICE,
-					ComponentName: KERBEROS_COMPONENT,
-					Hostname:      host.HostInfo.Hostname,
-				},
-			}
-			hostComponent, err := clientAmbari.CreateHostComponent(hostComponent)
-			if err != nil {
-				return err
-			}
-			log.Infof("Component %s is associated to host %s", KERBEROS_COMPONENT, host.HostInfo.Hostname)
-		} else {
-			log.Infof("Component %s is already associated to host %s", KERBEROS_COMPONENT, host.HostInfo.Hostname)
-		}
-
+		hostnames = append(hostnames, host.HostInfo.Hostname)
 	}
-
-	// Install service/component Kerberos on all nodes
-	serviceKerberos, err = clientAmbari.InstallService(serviceKerberos)
+	_, err = clientAmbari.AddService(c.String("cluster-name"), &client.ServiceSpec{
+		ServiceName:    KERBEROS_SERVICE,
+		Configurations: []client.Configuration{*configurationKerberosService, *configurationKerberosEnv},
+		Components: map[string][]string{
+			KERBEROS_COMPONENT: hostnames,
+		},
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/disaster37/go-ambari-rest/client"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func enableMaintenance(c *cli.Context) error {
+	return setMaintenance(c, client.MAINTENANCE_STATE_ON)
+}
+
+func disableMaintenance(c *cli.Context) error {
+	return setMaintenance(c, client.MAINTENANCE_STATE_OFF)
+}
+
+// setMaintenance permit to set the maintenance state on component on host, on host or on service
+// The target depend of the parameters set
+func setMaintenance(c *cli.Context, state string) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+
+	var previousState string
+	switch {
+	case c.String("component-name") != "":
+		if c.String("hostname") == "" {
+			return cli.NewExitError("You must set hostname parameter with component-name parameter", 1)
+		}
+		previousState, err = clientAmbari.SetHostComponentMaintenance(c.String("cluster-name"), c.String("hostname"), c.String("component-name"), state)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		log.Infof("Successfully turn %s maintenance mode for component %s on host %s (previous state: %s)", state, c.String("component-name"), c.String("hostname"), previousState)
+	case c.String("hostname") != "":
+		previousState, err = clientAmbari.SetHostMaintenance(c.String("cluster-name"), c.String("hostname"), state)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		log.Infof("Successfully turn %s maintenance mode for host %s (previous state: %s)", state, c.String("hostname"), previousState)
+	case c.String("service-name") != "":
+		previousState, err = clientAmbari.SetServiceMaintenance(c.String("cluster-name"), c.String("service-name"), state)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		log.Infof("Successfully turn %s maintenance mode for service %s (previous state: %s)", state, c.String("service-name"), previousState)
+	default:
+		return cli.NewExitError("You must set service-name, hostname or hostname and component-name parameters", 1)
+	}
+
+	return nil
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"fmt"
 	"github.com/disaster37/go-ambari-rest/client"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/urfave/cli.v1"
+	"strings"
+	"time"
 )
 
 func stopServiceInCluster(c *cli.Context) error {
@@ -113,3 +116,297 @@ func startAllServicesInCluster(c *cli.Context) error {
 
 	return nil
 }
+
+func listServicesInCluster(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("maintenance-state") != "" && c.String("maintenance-state") != client.MAINTENANCE_STATE_ON && c.String("maintenance-state") != client.MAINTENANCE_STATE_OFF {
+		return cli.NewExitError("The maintenance-state parameter must be ON or OFF", 1)
+	}
+
+	// Get the services
+	services, err := clientAmbari.Services(c.String("cluster-name"), strings.ToUpper(c.String("state")), c.String("maintenance-state"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Display them
+	w := newTableWriter()
+	fmt.Fprintln(w, "SERVICE\tSTATE\tMAINTENANCE\tREPOSITORY\tCOMPONENTS")
+	for _, service := range services {
+		components := make([]string, 0, len(service.Components))
+		for _, component := range service.Components {
+			components = append(components, component.ComponentInfo.ComponentName)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", service.ServiceInfo.ServiceName, service.ServiceInfo.State, service.ServiceInfo.MaintenanceState, service.ServiceInfo.RepositoryId, strings.Join(components, ","))
+	}
+
+	return w.Flush()
+}
+
+func restartServiceInCluster(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("service-name") == "" {
+		return cli.NewExitError("You must set service-name parameter", 1)
+	}
+
+	// Restart the service
+	err = clientAmbari.RestartService(c.String("cluster-name"), c.String("service-name"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Successfully restart service %s in cluster %s", c.String("service-name"), c.String("cluster-name"))
+
+	return nil
+}
+
+func restartComponentInCluster(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("service-name") == "" {
+		return cli.NewExitError("You must set service-name parameter", 1)
+	}
+	if c.String("component-name") == "" {
+		return cli.NewExitError("You must set component-name parameter", 1)
+	}
+	hostnames := make([]string, 0)
+	if c.String("hostnames") != "" {
+		hostnames = strings.Split(c.String("hostnames"), ",")
+	}
+
+	// Restart the component
+	err = clientAmbari.RestartComponent(c.String("cluster-name"), c.String("service-name"), c.String("component-name"), hostnames)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Successfully restart component %s in cluster %s", c.String("component-name"), c.String("cluster-name"))
+
+	return nil
+}
+
+func rollingRestartComponentInCluster(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("component-name") == "" {
+		return cli.NewExitError("You must set component-name parameter", 1)
+	}
+
+	// Run the rolling restart
+	requestSchedule, err := clientAmbari.RollingRestart(c.String("cluster-name"), c.String("component-name"), &client.RollingRestartOptions{
+		BatchSize:         int(c.Int64("batch-size")),
+		BatchInterval:     time.Duration(c.Int64("batch-interval")) * time.Second,
+		ToleratedFailures: int(c.Int64("tolerated-failures")),
+		OnlyStaleConfigs:  c.Bool("only-stale-configs"),
+		Timeout:           time.Duration(c.Int64("timeout")) * time.Second,
+	})
+	if requestSchedule != nil && requestSchedule.RequestScheduleInfo.Batch != nil {
+		w := newTableWriter()
+		fmt.Fprintln(w, "BATCH\tSTATUS\tREQUEST")
+		for _, batchRequest := range requestSchedule.RequestScheduleInfo.Batch.BatchRequests {
+			fmt.Fprintf(w, "%d\t%s\t%d\n", batchRequest.OrderId, batchRequest.Status, batchRequest.RequestId)
+		}
+		errFlush := w.Flush()
+		if errFlush != nil {
+			return cli.NewExitError(errFlush, 1)
+		}
+	}
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if requestSchedule == nil {
+		log.Infof("There are no component %s to restart in cluster %s", c.String("component-name"), c.String("cluster-name"))
+		return nil
+	}
+
+	log.Infof("Successfully rolling restart component %s in cluster %s", c.String("component-name"), c.String("cluster-name"))
+
+	return nil
+}
+
+func deleteServiceInCluster(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	if c.String("service-name") == "" {
+		return cli.NewExitError("You must set service-name parameter", 1)
+	}
+
+	// Delete the service
+	err = clientAmbari.DeleteServiceWithCheck(c.String("cluster-name"), c.String("service-name"), c.Bool("force"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Successfully delete service %s in cluster %s", c.String("service-name"), c.String("cluster-name"))
+
+	return nil
+}
+
+func serviceCheckInCluster(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+
+	// Get the services to check. If service name is not set, it check all installed services
+	serviceNames := make([]string, 0)
+	if c.String("service-name") != "" {
+		serviceNames = append(serviceNames, c.String("service-name"))
+	} else {
+		services, err := clientAmbari.Services(c.String("cluster-name"), "", "")
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		for _, service := range services {
+			if service.ServiceInfo.State != client.SERVICE_INIT && service.ServiceInfo.State != client.SERVICE_UNKNOWN {
+				serviceNames = append(serviceNames, service.ServiceInfo.ServiceName)
+			}
+		}
+	}
+
+	// Run the service checks
+	serviceChecks := make([]*client.ServiceCheck, 0, len(serviceNames))
+	nbFailed := 0
+	for _, serviceName := range serviceNames {
+		log.Infof("Run service check of %s", serviceName)
+		serviceCheck, err := clientAmbari.RunServiceCheck(c.String("cluster-name"), serviceName)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if serviceCheck.IsPassed() == false {
+			nbFailed++
+			for _, task := range serviceCheck.Tasks {
+				if task.IsFailed() {
+					log.Errorf("Service check of %s failed on %s: %s", serviceName, task.TaskInfo.Hostname, strings.TrimSpace(task.TaskInfo.Stderr))
+				}
+			}
+		}
+		serviceChecks = append(serviceChecks, serviceCheck)
+	}
+
+	// Display the result
+	w := newTableWriter()
+	fmt.Fprintln(w, "SERVICE\tRESULT\tSTATUS\tREQUEST")
+	for _, serviceCheck := range serviceChecks {
+		result := "PASSED"
+		if serviceCheck.IsPassed() == false {
+			result = "FAILED"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", serviceCheck.ServiceName, result, serviceCheck.Status, serviceCheck.RequestId)
+	}
+	err = w.Flush()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	if nbFailed > 0 {
+		return cli.NewExitError(fmt.Sprintf("%d service checks failed in cluster %s", nbFailed, c.String("cluster-name")), 1)
+	}
+	log.Infof("Successfully check %d services in cluster %s", len(serviceChecks), c.String("cluster-name"))
+
+	return nil
+}
+
+func startServicesInOrder(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	serviceNames := make([]string, 0)
+	if c.String("service-names") != "" {
+		serviceNames = strings.Split(c.String("service-names"), ",")
+	}
+
+	// Only display the order on dry run
+	if c.Bool("dry-run") == true {
+		order, err := clientAmbari.ServicesStartOrder(c.String("cluster-name"), serviceNames)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		log.Infof("Dry run: services will be started in this order: %s", strings.Join(order, ", "))
+		return nil
+	}
+
+	err = clientAmbari.StartServicesInOrder(c.String("cluster-name"), serviceNames, c.Bool("disable-maintenance"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Successfully start services in cluster %s", c.String("cluster-name"))
+
+	return nil
+}
+
+func stopServicesInOrder(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if c.String("cluster-name") == "" {
+		return cli.NewExitError("You must set cluster-name parameter", 1)
+	}
+	serviceNames := make([]string, 0)
+	if c.String("service-names") != "" {
+		serviceNames = strings.Split(c.String("service-names"), ",")
+	}
+
+	// Only display the order on dry run
+	if c.Bool("dry-run") == true {
+		order, err := clientAmbari.ServicesStopOrder(c.String("cluster-name"), serviceNames)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		log.Infof("Dry run: services will be stopped in this order: %s", strings.Join(order, ", "))
+		return nil
+	}
+
+	err = clientAmbari.StopServicesInOrder(c.String("cluster-name"), serviceNames, c.Bool("enable-maintenance"), c.Bool("force"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	log.Infof("Successfully stop services in cluster %s", c.String("cluster-name"))
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"github.com/disaster37/go-ambari-rest/client"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+	"strings"
+)
+
+func describeStack(c *cli.Context) error {
+
+	clientAmbari, err := manageGlobalParameters()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	stackName, stackVersion, err := manageStackParameters(clientAmbari, c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Describe all services
+	if c.String("service-name") == "" {
+		stackServices, err := clientAmbari.StackServices(stackName, stackVersion)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if stackServices == nil {
+			return cli.NewExitError(client.NewAmbariError(404, "Stack %s-%s not found", stackName, stackVersion), 1)
+		}
+		w := newTableWriter()
+		fmt.Fprintln(w, "SERVICE\tVERSION\tREQUIRED SERVICES\tDESCRIPTION")
+		for _, stackService := range stackServices {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", stackService.StackServiceInfo.ServiceName, stackService.StackServiceInfo.ServiceVersion, strings.Join(stackService.StackServiceInfo.RequiredServices, ","), stackService.StackServiceInfo.Comments)
+		}
+		return w.Flush()
+	}
+
+	// Describe the components of service
+	stackService, err := clientAmbari.StackService(stackName, stackVersion, c.String("service-name"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if stackService == nil {
+		return cli.NewExitError(client.NewAmbariError(404, "Service %s not found in stack %s-%s", c.String("service-name"), stackName, stackVersion), 1)
+	}
+	stackComponents, err := clientAmbari.StackComponents(stackName, stackVersion, c.String("service-name"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Printf("Service %s %s (required services: %s)\n\n", stackService.StackServiceInfo.ServiceName, stackService.StackServiceInfo.ServiceVersion, strings.Join(stackService.StackServiceInfo.RequiredServices, ","))
+	w := newTableWriter()
+	fmt.Fprintln(w, "COMPONENT\tCATEGORY\tCARDINALITY\tDEPENDENCIES")
+	for _, stackComponent := range stackComponents {
+		dependencies := make([]string, 0, len(stackComponent.Dependencies))
+		for _, dependency := range stackComponent.Dependencies {
+			dependencies = append(dependencies, fmt.Sprintf("%s/%s (%s)", dependency.StackDependencyInfo.ServiceName, dependency.StackDependencyInfo.ComponentName, dependency.StackDependencyInfo.Scope))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", stackComponent.StackComponentInfo.ComponentName, stackComponent.StackComponentInfo.Category, stackComponent.StackComponentInfo.Cardinality, strings.Join(dependencies, ","))
+	}
+	err = w.Flush()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Describe the configurations of service
+	if c.Bool("show-configurations") == true {
+		stackConfigurations, err := clientAmbari.StackConfigurations(stackName, stackVersion, c.String("service-name"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		fmt.Println()
+		w := newTableWriter()
+		fmt.Fprintln(w, "TYPE\tPROPERTY\tDEFAULT\tVALUE TYPE\tPROPERTY TYPE")
+		for _, stackConfiguration := range stackConfigurations {
+			valueType := ""
+			if stackConfiguration.StackConfigurationInfo.PropertyValueAttributes != nil {
+				valueType = stackConfiguration.StackConfigurationInfo.PropertyValueAttributes.Type
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", stackConfiguration.ConfigurationType(), stackConfiguration.StackConfigurationInfo.PropertyName, strings.Replace(stackConfiguration.StackConfigurationInfo.PropertyValue, "\n", " ", -1), valueType, strings.Join(stackConfiguration.StackConfigurationInfo.PropertyType, ","))
+		}
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// manageStackParameters permit to get the stack name and stack version from parameters or from the cluster
+func manageStackParameters(clientAmbari *client.AmbariClient, c *cli.Context) (string, string, error) {
+
+	if c.String("cluster-name") != "" {
+		cluster, err := clientAmbari.Cluster(c.String("cluster-name"))
+		if err != nil {
+			return "", "", err
+		}
+		if cluster == nil {
+			return "", "", client.NewAmbariError(404, "Cluster %s not found", c.String("cluster-name"))
+		}
+		stackName, stackVersion := cluster.StackNameAndVersion()
+		return stackName, stackVersion, nil
+	}
+
+	if c.String("stack-name") == "" {
+		return "", "", errors.New("You must set stack-name parameter")
+	}
+	if c.String("stack-version") == "" {
+		return "", "", errors.New("You must set stack-version parameter")
+	}
+
+	return c.String("stack-name"), c.String("stack-version"), nil
+}